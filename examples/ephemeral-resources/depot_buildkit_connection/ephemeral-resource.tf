@@ -0,0 +1,4 @@
+ephemeral "depot_buildkit_connection" "example" {
+  project_id = depot_project.example.id
+  platform   = "arm64"
+}
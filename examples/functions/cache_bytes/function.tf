@@ -0,0 +1,8 @@
+resource "depot_project" "example" {
+  name   = "something"
+  region = "us-east-1"
+
+  cache = {
+    size = provider::depot::cache_bytes("1TiB")
+  }
+}
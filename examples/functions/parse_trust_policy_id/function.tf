@@ -0,0 +1,3 @@
+output "trust_policy_project_id" {
+  value = provider::depot::parse_trust_policy_id("wkgrl762gp:3sxh2l0zvq").project_id
+}
@@ -0,0 +1,4 @@
+import {
+  to = depot_trust_policy.example
+  id = provider::depot::trust_policy_import_id("wkgrl762gp", "3sxh2l0zvq")
+}
@@ -0,0 +1,7 @@
+list "depot_project" "example" {
+  provider = depot
+
+  config {
+    region = "us-east-1"
+  }
+}
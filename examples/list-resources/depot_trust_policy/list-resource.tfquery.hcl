@@ -0,0 +1,7 @@
+list "depot_trust_policy" "example" {
+  provider = depot
+
+  config {
+    project_id = "wkgrl762gp"
+  }
+}
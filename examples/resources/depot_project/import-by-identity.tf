@@ -0,0 +1,7 @@
+import {
+  to = depot_project.example
+
+  identity = {
+    id = "wkgrl762gp"
+  }
+}
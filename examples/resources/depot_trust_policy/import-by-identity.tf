@@ -0,0 +1,8 @@
+import {
+  to = depot_trust_policy.example
+
+  identity = {
+    project_id = "wkgrl762gp"
+    id         = "nj38erubf2"
+  }
+}
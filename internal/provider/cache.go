@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/core/v1/corev1connect"
+	corev1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/core/v1"
+	"connectrpc.com/connect"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	trustPolicyCacheTTL = 30 * time.Second
+	// trustPolicyFetchTimeout bounds a shared list, which is not canceled with
+	// the context of its callers.
+	trustPolicyFetchTimeout = time.Minute
+)
+
+// trustPolicyCache caches the trust policies of projects for a short time, so
+// that refreshing many trust policies of the same project only lists them once.
+// Concurrent lists of the same project are coalesced into a single request.
+type trustPolicyCache struct {
+	mu      sync.Mutex
+	entries map[string]trustPolicyCacheEntry
+	// generations is bumped on every invalidation of a project so that lists
+	// started before the invalidation are neither joined nor cached.
+	generations  map[string]uint64
+	group        singleflight.Group
+	ttl          time.Duration
+	fetchTimeout time.Duration
+	now          func() time.Time
+}
+
+type trustPolicyCacheEntry struct {
+	response  *connect.Response[corev1.ListTrustPoliciesResponse]
+	expiresAt time.Time
+}
+
+func newTrustPolicyCache(ttl time.Duration) *trustPolicyCache {
+	return &trustPolicyCache{
+		entries:      map[string]trustPolicyCacheEntry{},
+		generations:  map[string]uint64{},
+		ttl:          ttl,
+		fetchTimeout: trustPolicyFetchTimeout,
+		now:          time.Now,
+	}
+}
+
+func (c *trustPolicyCache) list(ctx context.Context, projectId string, fetch func(context.Context) (*connect.Response[corev1.ListTrustPoliciesResponse], error)) (*connect.Response[corev1.ListTrustPoliciesResponse], error) {
+	c.mu.Lock()
+
+	if entry, ok := c.entries[projectId]; ok && c.now().Before(entry.expiresAt) {
+		c.mu.Unlock()
+		return entry.response, nil
+	}
+
+	generation := c.generations[projectId]
+	c.mu.Unlock()
+
+	// The list is shared by every caller coalesced onto it, so it is not canceled
+	// with the context of the first one but has its own timeout, and each caller
+	// only waits for it until its own context is done.
+	results := c.group.DoChan(fmt.Sprintf("%s/%d", projectId, generation), func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.fetchTimeout)
+		defer cancel()
+
+		response, err := fetch(fetchCtx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.generations[projectId] == generation {
+			c.entries[projectId] = trustPolicyCacheEntry{
+				response:  response,
+				expiresAt: c.now().Add(c.ttl),
+			}
+		}
+
+		return response, nil
+	})
+
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		return result.Val.(*connect.Response[corev1.ListTrustPoliciesResponse]), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *trustPolicyCache) invalidate(projectId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, projectId)
+	c.generations[projectId]++
+}
+
+// cachingProjectServiceClient serves ListTrustPolicies from a trustPolicyCache
+// and invalidates it whenever trust policies are added or removed.
+type cachingProjectServiceClient struct {
+	corev1connect.ProjectServiceClient
+	cache *trustPolicyCache
+}
+
+func newCachingProjectServiceClient(client corev1connect.ProjectServiceClient, cache *trustPolicyCache) corev1connect.ProjectServiceClient {
+	return &cachingProjectServiceClient{
+		ProjectServiceClient: client,
+		cache:                cache,
+	}
+}
+
+func (c *cachingProjectServiceClient) ListTrustPolicies(ctx context.Context, req *connect.Request[corev1.ListTrustPoliciesRequest]) (*connect.Response[corev1.ListTrustPoliciesResponse], error) {
+	return c.cache.list(ctx, req.Msg.ProjectId, func(ctx context.Context) (*connect.Response[corev1.ListTrustPoliciesResponse], error) {
+		return c.ProjectServiceClient.ListTrustPolicies(ctx, req)
+	})
+}
+
+func (c *cachingProjectServiceClient) AddTrustPolicy(ctx context.Context, req *connect.Request[corev1.AddTrustPolicyRequest]) (*connect.Response[corev1.AddTrustPolicyResponse], error) {
+	defer c.cache.invalidate(req.Msg.ProjectId)
+
+	return c.ProjectServiceClient.AddTrustPolicy(ctx, req)
+}
+
+func (c *cachingProjectServiceClient) RemoveTrustPolicy(ctx context.Context, req *connect.Request[corev1.RemoveTrustPolicyRequest]) (*connect.Response[corev1.RemoveTrustPolicyResponse], error) {
+	defer c.cache.invalidate(req.Msg.ProjectId)
+
+	return c.ProjectServiceClient.RemoveTrustPolicy(ctx, req)
+}
+
+func (c *cachingProjectServiceClient) DeleteProject(ctx context.Context, req *connect.Request[corev1.DeleteProjectRequest]) (*connect.Response[corev1.DeleteProjectResponse], error) {
+	defer c.cache.invalidate(req.Msg.ProjectId)
+
+	return c.ProjectServiceClient.DeleteProject(ctx, req)
+}
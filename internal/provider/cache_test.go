@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/core/v1"
+	"connectrpc.com/connect"
+)
+
+func countingFetch(calls *int64, delay time.Duration) func(context.Context) (*connect.Response[corev1.ListTrustPoliciesResponse], error) {
+	return func(ctx context.Context) (*connect.Response[corev1.ListTrustPoliciesResponse], error) {
+		atomic.AddInt64(calls, 1)
+		time.Sleep(delay)
+
+		return connect.NewResponse(&corev1.ListTrustPoliciesResponse{
+			TrustPolicies: []*corev1.TrustPolicy{{TrustPolicyId: "policy"}},
+		}), nil
+	}
+}
+
+func TestTrustPolicyCacheCoalescesConcurrentLists(t *testing.T) {
+	cache := newTrustPolicyCache(time.Minute)
+
+	var calls int64
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := cache.list(context.Background(), "project", countingFetch(&calls, 20*time.Millisecond)); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected 1 list call, got %d", calls)
+	}
+}
+
+func TestTrustPolicyCacheExpires(t *testing.T) {
+	cache := newTrustPolicyCache(time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	var calls int64
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.list(context.Background(), "project", countingFetch(&calls, 0)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected 1 list call before expiry, got %d", calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if _, err := cache.list(context.Background(), "project", countingFetch(&calls, 0)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected 2 list calls after expiry, got %d", calls)
+	}
+}
+
+func TestTrustPolicyCacheKeyedByProject(t *testing.T) {
+	cache := newTrustPolicyCache(time.Minute)
+
+	var calls int64
+
+	for _, projectId := range []string{"one", "two", "one", "two"} {
+		if _, err := cache.list(context.Background(), projectId, countingFetch(&calls, 0)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected 2 list calls, got %d", calls)
+	}
+}
+
+func TestTrustPolicyCacheInvalidate(t *testing.T) {
+	cache := newTrustPolicyCache(time.Minute)
+
+	var calls int64
+
+	if _, err := cache.list(context.Background(), "project", countingFetch(&calls, 0)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cache.invalidate("project")
+
+	if _, err := cache.list(context.Background(), "project", countingFetch(&calls, 0)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected 2 list calls after invalidation, got %d", calls)
+	}
+}
+
+func TestTrustPolicyCacheInvalidateDuringList(t *testing.T) {
+	cache := newTrustPolicyCache(time.Minute)
+
+	var calls int64
+
+	fetch := func(ctx context.Context) (*connect.Response[corev1.ListTrustPoliciesResponse], error) {
+		atomic.AddInt64(&calls, 1)
+		// A trust policy is added while the list is in flight.
+		cache.invalidate("project")
+
+		return connect.NewResponse(&corev1.ListTrustPoliciesResponse{}), nil
+	}
+
+	if _, err := cache.list(context.Background(), "project", fetch); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := cache.list(context.Background(), "project", countingFetch(&calls, 0)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected stale list not to be cached, got %d calls", calls)
+	}
+}
+
+func TestTrustPolicyCacheFirstCallerCanceled(t *testing.T) {
+	cache := newTrustPolicyCache(time.Minute)
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+
+	fetch := func(ctx context.Context) (*connect.Response[corev1.ListTrustPoliciesResponse], error) {
+		close(started)
+		<-unblock
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		return connect.NewResponse(&corev1.ListTrustPoliciesResponse{
+			TrustPolicies: []*corev1.TrustPolicy{{TrustPolicyId: "policy"}},
+		}), nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+
+	go func() {
+		_, err := cache.list(ctx, "project", fetch)
+		firstErr <- err
+	}()
+
+	<-started
+
+	secondErr := make(chan error, 1)
+
+	go func() {
+		_, err := cache.list(context.Background(), "project", fetch)
+		secondErr <- err
+	}()
+
+	cancel()
+
+	if err := <-firstErr; err != context.Canceled {
+		t.Fatalf("expected the first caller to be canceled, got %v", err)
+	}
+
+	close(unblock)
+
+	if err := <-secondErr; err != nil {
+		t.Fatalf("expected the second caller to get the list, got error: %s", err)
+	}
+}
+
+func TestTrustPolicyCacheFetchTimeout(t *testing.T) {
+	cache := newTrustPolicyCache(time.Minute)
+	cache.fetchTimeout = 50 * time.Millisecond
+
+	started := make(chan struct{})
+
+	// The first list hangs until its context is done.
+	hung := func(ctx context.Context) (*connect.Response[corev1.ListTrustPoliciesResponse], error) {
+		close(started)
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	firstErr := make(chan error, 1)
+
+	go func() {
+		_, err := cache.list(context.Background(), "project", hung)
+		firstErr <- err
+	}()
+
+	<-started
+
+	var calls int64
+
+	// The second caller joins the hung list and gets an answer once it times out.
+	if _, err := cache.list(context.Background(), "project", countingFetch(&calls, 0)); err != context.DeadlineExceeded {
+		t.Fatalf("expected the joined list to time out, got %v", err)
+	}
+
+	if err := <-firstErr; err != context.DeadlineExceeded {
+		t.Fatalf("expected the hung list to time out, got %v", err)
+	}
+
+	// Later callers are not stuck behind the hung list.
+	if _, err := cache.list(context.Background(), "project", countingFetch(&calls, 0)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected 1 new list call after the timeout, got %d", calls)
+	}
+}
@@ -0,0 +1,366 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/build/v1/buildv1connect"
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/buildkit/v1/buildkitv1connect"
+	buildv1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/build/v1"
+	buildkitv1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/buildkit/v1"
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ ephemeral.EphemeralResource = &BuildKitConnectionEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &BuildKitConnectionEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithRenew = &BuildKitConnectionEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &BuildKitConnectionEphemeralResource{}
+
+const (
+	buildKitConnectionPrivateKey     = "build"
+	buildKitConnectionRenewInterval  = time.Minute
+	buildKitConnectionCleanupTimeout = 30 * time.Second
+)
+
+var buildKitPlatforms = map[string]buildkitv1.Platform{
+	"amd64": buildkitv1.Platform_PLATFORM_AMD64,
+	"arm64": buildkitv1.Platform_PLATFORM_ARM64,
+}
+
+func NewBuildKitConnectionEphemeralResource() ephemeral.EphemeralResource {
+	return &BuildKitConnectionEphemeralResource{}
+}
+
+type BuildKitConnectionEphemeralResource struct {
+	buildClient    buildv1connect.BuildServiceClient
+	buildKitClient buildkitv1connect.BuildKitServiceClient
+}
+
+type BuildKitConnectionEphemeralResourceModel struct {
+	ProjectId  types.String `tfsdk:"project_id"`
+	Platform   types.String `tfsdk:"platform"`
+	BuildId    types.String `tfsdk:"build_id"`
+	Endpoint   types.String `tfsdk:"endpoint"`
+	ServerName types.String `tfsdk:"server_name"`
+	CaCert     types.String `tfsdk:"ca_cert"`
+	Cert       types.String `tfsdk:"cert"`
+	Key        types.String `tfsdk:"key"`
+}
+
+// buildKitConnectionPrivateData is kept by Terraform between Open, Renew and
+// Close to release the builder.
+type buildKitConnectionPrivateData struct {
+	BuildId    string `json:"build_id"`
+	BuildToken string `json:"build_token"`
+	Platform   string `json:"platform"`
+}
+
+func (r *BuildKitConnectionEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_buildkit_connection"
+}
+
+func (r *BuildKitConnectionEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Connection details of a Depot BuildKit builder for a project. A build is started when the resource is opened and finished when it is closed.\n\n~> **Note:** Terraform opens ephemeral resources during `plan` as well as `apply`, so every plan and every apply starts a build and provisions a builder which is billed by Depot.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the project to build.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthAtLeast(1),
+				},
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Platform of the builder, `amd64` or `arm64`. **Default** `amd64`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("amd64", "arm64"),
+				},
+			},
+			"build_id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the build.",
+				Computed:            true,
+			},
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "BuildKit endpoint of the builder.",
+				Computed:            true,
+			},
+			"server_name": schema.StringAttribute{
+				MarkdownDescription: "TLS server name of the builder.",
+				Computed:            true,
+			},
+			"ca_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificate of the builder.",
+				Computed:            true,
+			},
+			"cert": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded client certificate for the builder.",
+				Computed:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded client private key for the builder.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *BuildKitConnectionEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data := getProviderData(req.ProviderData, &resp.Diagnostics)
+
+	if data == nil {
+		return
+	}
+
+	r.buildClient = data.BuildClient
+	r.buildKitClient = data.BuildKitClient
+}
+
+func (r *BuildKitConnectionEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data *BuildKitConnectionEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Platform.IsNull() || data.Platform.IsUnknown() {
+		data.Platform = types.StringValue("amd64")
+	}
+
+	build, err := r.buildClient.CreateBuild(ctx, &connect.Request[buildv1.CreateBuildRequest]{
+		Msg: &buildv1.CreateBuildRequest{
+			ProjectId: data.ProjectId.ValueString(),
+		},
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create build, got error: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "created a build")
+
+	private := buildKitConnectionPrivateData{
+		BuildId:    build.Msg.BuildId,
+		BuildToken: build.Msg.BuildToken,
+		Platform:   data.Platform.ValueString(),
+	}
+
+	active, err := r.getEndpoint(ctx, private)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to acquire builder, got error: %s", err))
+		r.cleanup(ctx, private, false, err.Error(), &resp.Diagnostics)
+		return
+	}
+
+	tflog.Trace(ctx, "acquired a builder")
+
+	data.BuildId = types.StringValue(private.BuildId)
+	data.Endpoint = types.StringValue(active.Endpoint)
+	data.ServerName = types.StringValue(active.ServerName)
+	data.CaCert = types.StringValue(active.GetCaCert().GetCert())
+	data.Cert = types.StringValue(active.GetCert().GetCert().GetCert())
+	data.Key = types.StringValue(active.GetCert().GetKey().GetKey())
+
+	privateBytes, err := json.Marshal(private)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Internal Error", fmt.Sprintf("Unable to store build, got error: %s", err))
+		r.cleanup(ctx, private, true, err.Error(), &resp.Diagnostics)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, buildKitConnectionPrivateKey, privateBytes)...)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		r.cleanup(ctx, private, true, "unable to open the BuildKit connection", &resp.Diagnostics)
+		return
+	}
+
+	resp.RenewAt = time.Now().Add(buildKitConnectionRenewInterval)
+}
+
+func (r *BuildKitConnectionEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	private, ok := r.getPrivateData(ctx, req.Private, &resp.Diagnostics)
+
+	if !ok {
+		return
+	}
+
+	stream := r.buildKitClient.ReportHealth(ctx)
+	stream.RequestHeader().Set("Authorization", "Bearer "+private.BuildToken)
+
+	err := stream.Send(&buildkitv1.ReportHealthRequest{
+		BuildId:  private.BuildId,
+		Platform: buildKitPlatforms[private.Platform],
+	})
+
+	// The stream is always closed to release it, and a failed send is only
+	// explained by the error received when closing it.
+	if _, closeErr := stream.CloseAndReceive(); err == nil || errors.Is(err, io.EOF) {
+		err = closeErr
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to report builder health, got error: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "reported builder health")
+
+	resp.RenewAt = time.Now().Add(buildKitConnectionRenewInterval)
+}
+
+func (r *BuildKitConnectionEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	private, ok := r.getPrivateData(ctx, req.Private, &resp.Diagnostics)
+
+	if !ok {
+		return
+	}
+
+	if err := r.releaseEndpoint(ctx, private); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to release builder, got error: %s", err))
+	} else {
+		tflog.Trace(ctx, "released a builder")
+	}
+
+	if err := r.finishBuild(ctx, private, ""); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to finish build, got error: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "finished a build")
+}
+
+// cleanup releases the builder when it was acquired and fails the build after
+// Open could not complete. Open usually fails because its context was canceled,
+// so the requests are sent with a context which is not canceled with it.
+func (r *BuildKitConnectionEphemeralResource) cleanup(ctx context.Context, private buildKitConnectionPrivateData, acquired bool, errMsg string, diags *diag.Diagnostics) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), buildKitConnectionCleanupTimeout)
+	defer cancel()
+
+	if acquired {
+		if err := r.releaseEndpoint(ctx, private); err != nil {
+			diags.AddWarning("Client Error", fmt.Sprintf("Unable to release builder, got error: %s", err))
+		}
+	}
+
+	if err := r.finishBuild(ctx, private, errMsg); err != nil {
+		diags.AddWarning("Client Error", fmt.Sprintf("Unable to finish build, got error: %s", err))
+	}
+}
+
+// releaseEndpoint releases the builder of the build.
+func (r *BuildKitConnectionEphemeralResource) releaseEndpoint(ctx context.Context, private buildKitConnectionPrivateData) error {
+	request := connect.NewRequest(&buildkitv1.ReleaseEndpointRequest{
+		BuildId:  private.BuildId,
+		Platform: buildKitPlatforms[private.Platform],
+	})
+
+	request.Header().Set("Authorization", "Bearer "+private.BuildToken)
+
+	_, err := r.buildKitClient.ReleaseEndpoint(ctx, request)
+
+	return err
+}
+
+// getEndpoint waits for the builder of the build to be active.
+func (r *BuildKitConnectionEphemeralResource) getEndpoint(ctx context.Context, private buildKitConnectionPrivateData) (*buildkitv1.GetEndpointResponse_ActiveConnection, error) {
+	request := connect.NewRequest(&buildkitv1.GetEndpointRequest{
+		BuildId:  private.BuildId,
+		Platform: buildKitPlatforms[private.Platform],
+	})
+
+	request.Header().Set("Authorization", "Bearer "+private.BuildToken)
+
+	stream, err := r.buildKitClient.GetEndpoint(ctx, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer stream.Close()
+
+	for stream.Receive() {
+		if active := stream.Msg().GetActive(); active != nil {
+			return active, nil
+		}
+
+		tflog.Trace(ctx, "waiting for builder")
+	}
+
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("builder connection closed before it was active")
+}
+
+// finishBuild marks the build as finished, as failed when errMsg is not empty.
+func (r *BuildKitConnectionEphemeralResource) finishBuild(ctx context.Context, private buildKitConnectionPrivateData, errMsg string) error {
+	input := &buildv1.FinishBuildRequest{
+		BuildId: private.BuildId,
+		Result: &buildv1.FinishBuildRequest_Success{
+			Success: &buildv1.FinishBuildRequest_BuildSuccess{},
+		},
+	}
+
+	if errMsg != "" {
+		input.Result = &buildv1.FinishBuildRequest_Error{
+			Error: &buildv1.FinishBuildRequest_BuildError{
+				Error: errMsg,
+			},
+		}
+	}
+
+	request := connect.NewRequest(input)
+	request.Header().Set("Authorization", "Bearer "+private.BuildToken)
+
+	_, err := r.buildClient.FinishBuild(ctx, request)
+
+	return err
+}
+
+type privateDataGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+func (r *BuildKitConnectionEphemeralResource) getPrivateData(ctx context.Context, private privateDataGetter, diags *diag.Diagnostics) (buildKitConnectionPrivateData, bool) {
+	var data buildKitConnectionPrivateData
+
+	privateBytes, getDiags := private.GetKey(ctx, buildKitConnectionPrivateKey)
+	diags.Append(getDiags...)
+
+	if diags.HasError() {
+		return data, false
+	}
+
+	if err := json.Unmarshal(privateBytes, &data); err != nil {
+		diags.AddError("Internal Error", fmt.Sprintf("Unable to read build, got error: %s", err))
+		return data, false
+	}
+
+	return data, true
+}
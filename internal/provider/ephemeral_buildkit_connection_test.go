@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/build/v1/buildv1connect"
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/buildkit/v1/buildkitv1connect"
+	buildv1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/build/v1"
+	buildkitv1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/buildkit/v1"
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccBuildKitConnectionEphemeralResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"depot": testAccProtoV6ProviderFactories["depot"],
+			"echo":  echoprovider.NewProviderServer(),
+		},
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			// Open and Close testing
+			{
+				Config: `
+ephemeral "depot_buildkit_connection" "test" {
+  project_id = "7f76t7vghb"
+}
+
+provider "echo" {
+  data = {
+    platform = ephemeral.depot_buildkit_connection.test.platform
+    endpoint = ephemeral.depot_buildkit_connection.test.endpoint
+  }
+}
+
+resource "echo" "test" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("platform"), knownvalue.StringExact("amd64")),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("endpoint"), knownvalue.NotNull()),
+				},
+			},
+		},
+	})
+}
+
+// fakeBuildServiceClient records the builds which were finished and whether
+// their context was already done.
+type fakeBuildServiceClient struct {
+	buildv1connect.BuildServiceClient
+
+	finished    []*buildv1.FinishBuildRequest
+	finishedErr error
+}
+
+func (c *fakeBuildServiceClient) CreateBuild(ctx context.Context, req *connect.Request[buildv1.CreateBuildRequest]) (*connect.Response[buildv1.CreateBuildResponse], error) {
+	return connect.NewResponse(&buildv1.CreateBuildResponse{BuildId: "build", BuildToken: "build-token"}), nil
+}
+
+func (c *fakeBuildServiceClient) FinishBuild(ctx context.Context, req *connect.Request[buildv1.FinishBuildRequest]) (*connect.Response[buildv1.FinishBuildResponse], error) {
+	c.finished = append(c.finished, req.Msg)
+	c.finishedErr = ctx.Err()
+
+	return connect.NewResponse(&buildv1.FinishBuildResponse{}), nil
+}
+
+// fakeBuildKitServiceClient cancels the context of Open while waiting for the
+// builder, like a user interrupting Terraform.
+type fakeBuildKitServiceClient struct {
+	buildkitv1connect.BuildKitServiceClient
+
+	cancel context.CancelFunc
+}
+
+func (c *fakeBuildKitServiceClient) GetEndpoint(ctx context.Context, req *connect.Request[buildkitv1.GetEndpointRequest]) (*connect.ServerStreamForClient[buildkitv1.GetEndpointResponse], error) {
+	c.cancel()
+
+	return nil, ctx.Err()
+}
+
+func TestBuildKitConnectionEphemeralResourceOpenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	buildClient := &fakeBuildServiceClient{}
+	r := &BuildKitConnectionEphemeralResource{
+		buildClient:    buildClient,
+		buildKitClient: &fakeBuildKitServiceClient{cancel: cancel},
+	}
+
+	var schemaResp ephemeral.SchemaResponse
+
+	r.Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	values["project_id"] = tftypes.NewValue(tftypes.String, "project")
+
+	req := ephemeral.OpenRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+	}
+
+	resp := ephemeral.OpenResponse{
+		Result: tfsdk.EphemeralResultData{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+
+	r.Open(ctx, req, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error diagnostic")
+	}
+
+	if len(buildClient.finished) != 1 || buildClient.finished[0].GetError() == nil {
+		t.Fatalf("expected the build to be finished with an error, got %v", buildClient.finished)
+	}
+
+	if buildClient.finishedErr != nil {
+		t.Errorf("expected the build to be finished with a live context, got %s", buildClient.finishedErr)
+	}
+}
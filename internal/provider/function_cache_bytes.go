@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &CacheBytesFunction{}
+
+var cacheSizeRegexp = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)$`)
+
+// cacheSizeUnits are binary like the `size` of the cache of a project.
+var cacheSizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"kb":  1024,
+	"kib": 1024,
+	"mb":  1024 * 1024,
+	"mib": 1024 * 1024,
+	"gb":  sizeGB,
+	"gib": sizeGB,
+	"tb":  1024 * sizeGB,
+	"tib": 1024 * sizeGB,
+}
+
+func NewCacheBytesFunction() function.Function {
+	return &CacheBytesFunction{}
+}
+
+type CacheBytesFunction struct{}
+
+func (f *CacheBytesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cache_bytes"
+}
+
+func (f *CacheBytesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Converts a human readable size to the cache size of a project.",
+		MarkdownDescription: "Converts a human readable size, e.g. `512GiB` or `1.5TB`, to the number of GB expected by the `cache.size` attribute of the `depot_project` resource. Units are binary, so `1GB` and `1GiB` are both 1024^3 bytes, and a number without unit is a number of bytes. The size must be a whole number of GB.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "size",
+				MarkdownDescription: "Human readable size.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *CacheBytesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var size string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &size))
+
+	if resp.Error != nil {
+		return
+	}
+
+	gb, err := parseCacheSize(size)
+
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, gb))
+}
+
+// parseCacheSize converts a human readable size to a whole number of GB.
+func parseCacheSize(size string) (int64, error) {
+	matches := cacheSizeRegexp.FindStringSubmatch(strings.TrimSpace(size))
+
+	if matches == nil {
+		return 0, fmt.Errorf("Expected a size like 50GB, got: %q", size)
+	}
+
+	unit, ok := cacheSizeUnits[strings.ToLower(matches[2])]
+
+	if !ok {
+		return 0, fmt.Errorf("Unknown size unit %q, expected one of B, KB, KiB, MB, MiB, GB, GiB, TB or TiB", matches[2])
+	}
+
+	value, ok := new(big.Rat).SetString(matches[1])
+
+	if !ok {
+		return 0, fmt.Errorf("Expected a size like 50GB, got: %q", size)
+	}
+
+	value.Mul(value, new(big.Rat).SetInt64(unit))
+	value.Quo(value, new(big.Rat).SetInt64(sizeGB))
+
+	if !value.IsInt() || !value.Num().IsInt64() {
+		return 0, fmt.Errorf("Size must be a whole number of GB, got: %q", size)
+	}
+
+	return value.Num().Int64(), nil
+}
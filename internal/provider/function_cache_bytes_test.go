@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestParseCacheSize(t *testing.T) {
+	cases := map[string]int64{
+		"50GB":           50,
+		"50 GB":          50,
+		"50gib":          50,
+		"1TB":            1024,
+		"1.5TiB":         1536,
+		"2048MB":         2,
+		"1073741824":     1,
+		"1073741824B":    1,
+		" 25GB ":         25,
+		"0GB":            0,
+		"1048576KiB":     1,
+		"0.5TB":          512,
+		"100.000GB":      100,
+		"10995116277760": 10240,
+	}
+
+	for size, expected := range cases {
+		actual, err := parseCacheSize(size)
+
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", size, err)
+			continue
+		}
+
+		if actual != expected {
+			t.Errorf("%q: expected %d, got %d", size, expected, actual)
+		}
+	}
+
+	for _, size := range []string{"", "GB", "50XB", "-1GB", "1.5GB", "100MB", "abc"} {
+		if _, err := parseCacheSize(size); err == nil {
+			t.Errorf("%q: expected error", size)
+		}
+	}
+}
+
+func TestAccCacheBytesFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::depot::cache_bytes("1.5TiB")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.Int64Exact(1536)),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::depot::cache_bytes("100MB")
+}
+`,
+				ExpectError: regexp.MustCompile("Size must be a whole number of GB"),
+			},
+		},
+	})
+}
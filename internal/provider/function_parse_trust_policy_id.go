@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &ParseTrustPolicyIdFunction{}
+
+var trustPolicyIdAttrTypes = map[string]attr.Type{
+	"project_id": types.StringType,
+	"id":         types.StringType,
+}
+
+func NewParseTrustPolicyIdFunction() function.Function {
+	return &ParseTrustPolicyIdFunction{}
+}
+
+type ParseTrustPolicyIdFunction struct{}
+
+func (f *ParseTrustPolicyIdFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_trust_policy_id"
+}
+
+func (f *ParseTrustPolicyIdFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Parses the import identifier of a trust policy.",
+		MarkdownDescription: "Parses the import identifier of a trust policy with the format `project_id:trust_policy_id` into an object with the `project_id` and `id` attributes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "import_id",
+				MarkdownDescription: "Import identifier of the trust policy.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: trustPolicyIdAttrTypes,
+		},
+	}
+}
+
+func (f *ParseTrustPolicyIdFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var importId string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &importId))
+
+	if resp.Error != nil {
+		return
+	}
+
+	projectId, id, err := parseTrustPolicyImportId(importId)
+
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result := types.ObjectValueMust(
+		trustPolicyIdAttrTypes,
+		map[string]attr.Value{
+			"project_id": types.StringValue(projectId),
+			"id":         types.StringValue(id),
+		},
+	)
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestParseTrustPolicyImportId(t *testing.T) {
+	projectId, id, err := parseTrustPolicyImportId(formatTrustPolicyImportId("7f76t7vghb", "abc123"))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if projectId != "7f76t7vghb" || id != "abc123" {
+		t.Fatalf("expected 7f76t7vghb and abc123, got %q and %q", projectId, id)
+	}
+
+	for _, importId := range []string{"", "7f76t7vghb", "7f76t7vghb:", ":abc123", "a:b:c"} {
+		if _, _, err := parseTrustPolicyImportId(importId); err == nil {
+			t.Errorf("%q: expected error", importId)
+		}
+	}
+}
+
+func TestAccParseTrustPolicyIdFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::depot::parse_trust_policy_id("7f76t7vghb:abc123")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.ObjectExact(map[string]knownvalue.Check{
+						"project_id": knownvalue.StringExact("7f76t7vghb"),
+						"id":         knownvalue.StringExact("abc123"),
+					})),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::depot::parse_trust_policy_id("abc123")
+}
+`,
+				ExpectError: regexp.MustCompile("Expected import identifier with format"),
+			},
+		},
+	})
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &TrustPolicyImportIdFunction{}
+
+func NewTrustPolicyImportIdFunction() function.Function {
+	return &TrustPolicyImportIdFunction{}
+}
+
+type TrustPolicyImportIdFunction struct{}
+
+func (f *TrustPolicyImportIdFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "trust_policy_import_id"
+}
+
+func (f *TrustPolicyImportIdFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Returns the import identifier of a trust policy.",
+		MarkdownDescription: "Returns the import identifier of a trust policy with the format `project_id:trust_policy_id`, as expected by the `depot_trust_policy` resource.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "project_id",
+				MarkdownDescription: "Identifier of the project for the trust policy.",
+			},
+			function.StringParameter{
+				Name:                "id",
+				MarkdownDescription: "Identifier of the trust policy.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *TrustPolicyImportIdFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var projectId, id string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &projectId, &id))
+
+	if resp.Error != nil {
+		return
+	}
+
+	if projectId == "" || strings.Contains(projectId, ":") {
+		resp.Error = function.NewArgumentFuncError(0, "Project identifier must not be empty or contain a colon.")
+		return
+	}
+
+	if id == "" || strings.Contains(id, ":") {
+		resp.Error = function.NewArgumentFuncError(1, "Trust policy identifier must not be empty or contain a colon.")
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, formatTrustPolicyImportId(projectId, id)))
+}
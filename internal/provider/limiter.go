@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+
+	"connectrpc.com/connect"
+	"golang.org/x/time/rate"
+)
+
+// requestLimiter limits the rate and the number of in-flight requests made to
+// the Depot API. It is shared by all the resources of a provider instance.
+type requestLimiter struct {
+	// rate is nil when the number of requests per second is not limited.
+	rate *rate.Limiter
+	// slots is nil when the number of concurrent requests is not limited.
+	slots chan struct{}
+}
+
+// newRequestLimiter creates a limiter allowing requestsPerSecond requests per
+// second and maxConcurrent requests in flight. Zero disables the limit.
+func newRequestLimiter(requestsPerSecond float64, maxConcurrent int64) *requestLimiter {
+	l := &requestLimiter{}
+
+	if requestsPerSecond > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(requestsPerSecond), int(math.Max(1, math.Ceil(requestsPerSecond))))
+	}
+
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+
+	return l
+}
+
+// acquire blocks until a request is allowed to be sent. The returned function
+// must be called once the request has finished.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			release()
+
+			// Wait fails before the deadline when the request could not be
+			// allowed in time.
+			if ctx.Err() == nil {
+				err = fmt.Errorf("%w: %s", context.DeadlineExceeded, err)
+			}
+
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// limiterError converts an error returned by acquire to the error of the
+// request, keeping whether its deadline was exceeded or it was canceled.
+func limiterError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return connect.NewError(connect.CodeDeadlineExceeded, err)
+	}
+
+	return connect.NewError(connect.CodeCanceled, err)
+}
+
+// interceptor applies the limiter to unary and streaming RPCs. A stream holds
+// its slot until it is closed.
+func (l *requestLimiter) interceptor() connect.Interceptor {
+	return &limiterInterceptor{limiter: l}
+}
+
+type limiterInterceptor struct {
+	limiter *requestLimiter
+}
+
+func (i *limiterInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		release, err := i.limiter.acquire(ctx)
+
+		if err != nil {
+			return nil, limiterError(err)
+		}
+
+		defer release()
+
+		return next(ctx, req)
+	}
+}
+
+func (i *limiterInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		release, err := i.limiter.acquire(ctx)
+
+		if err != nil {
+			return &failedStreamingClientConn{
+				spec:          spec,
+				err:           limiterError(err),
+				requestHeader: http.Header{},
+			}
+		}
+
+		return &limitedStreamingClientConn{
+			StreamingClientConn: next(ctx, spec),
+			release:             release,
+		}
+	}
+}
+
+func (i *limiterInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// limitedStreamingClientConn releases its slot of the limiter once the
+// response is closed.
+type limitedStreamingClientConn struct {
+	connect.StreamingClientConn
+
+	release func()
+	once    sync.Once
+}
+
+func (c *limitedStreamingClientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+
+	c.once.Do(c.release)
+
+	return err
+}
+
+// failedStreamingClientConn is a stream which was never opened because the
+// limiter did not allow it, returning the error of the limiter.
+type failedStreamingClientConn struct {
+	spec          connect.Spec
+	err           error
+	requestHeader http.Header
+}
+
+func (c *failedStreamingClientConn) Spec() connect.Spec           { return c.spec }
+func (c *failedStreamingClientConn) Peer() connect.Peer           { return connect.Peer{} }
+func (c *failedStreamingClientConn) Send(any) error               { return c.err }
+func (c *failedStreamingClientConn) RequestHeader() http.Header   { return c.requestHeader }
+func (c *failedStreamingClientConn) CloseRequest() error          { return nil }
+func (c *failedStreamingClientConn) Receive(any) error            { return c.err }
+func (c *failedStreamingClientConn) ResponseHeader() http.Header  { return http.Header{} }
+func (c *failedStreamingClientConn) ResponseTrailer() http.Header { return http.Header{} }
+func (c *failedStreamingClientConn) CloseResponse() error         { return nil }
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/core/v1"
+	"connectrpc.com/connect"
+)
+
+func TestRequestLimiterMaxConcurrent(t *testing.T) {
+	limiter := newRequestLimiter(0, 2)
+
+	var inFlight, maxInFlight int64
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			release, err := limiter.acquire(context.Background())
+
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+
+			defer release()
+
+			current := atomic.AddInt64(&inFlight, 1)
+
+			for {
+				observed := atomic.LoadInt64(&maxInFlight)
+
+				if current <= observed || atomic.CompareAndSwapInt64(&maxInFlight, observed, current) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt64(&inFlight, -1)
+		}()
+	}
+
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Fatalf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestRequestLimiterCanceled(t *testing.T) {
+	limiter := newRequestLimiter(0, 1)
+
+	release, err := limiter.acquire(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := limiter.acquire(ctx); err == nil {
+		t.Fatal("expected error when no slot is available")
+	}
+}
+
+func TestRequestLimiterRequestsPerSecond(t *testing.T) {
+	limiter := newRequestLimiter(50, 0)
+	start := time.Now()
+
+	// The first 50 requests are allowed at once, the next 10 are spaced by 20ms.
+	for i := 0; i < 60; i++ {
+		if _, err := limiter.acquire(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected requests to be spaced out, 60 requests took %s", elapsed)
+	}
+}
+
+func TestRequestLimiterErrorCodes(t *testing.T) {
+	limiter := newRequestLimiter(0.1, 1)
+
+	call := limiter.interceptor().WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return connect.NewResponse(&corev1.ListProjectsResponse{}), nil
+	})
+
+	if _, err := call(context.Background(), connect.NewRequest(&corev1.ListProjectsRequest{})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The next request is only allowed in 10 seconds.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := call(ctx, connect.NewRequest(&corev1.ListProjectsRequest{})); connect.CodeOf(err) != connect.CodeDeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := call(ctx, connect.NewRequest(&corev1.ListProjectsRequest{})); connect.CodeOf(err) != connect.CodeCanceled {
+		t.Errorf("expected canceled, got %v", err)
+	}
+}
+
+func TestRequestLimiterUnlimited(t *testing.T) {
+	limiter := newRequestLimiter(0, 0)
+
+	for i := 0; i < 100; i++ {
+		if _, err := limiter.acquire(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+// stubStreamingClientConn records whether the response of the stream was
+// closed.
+type stubStreamingClientConn struct {
+	connect.StreamingClientConn
+
+	closed bool
+}
+
+func (c *stubStreamingClientConn) CloseResponse() error {
+	c.closed = true
+
+	return nil
+}
+
+func TestRequestLimiterStreamingHoldsSlot(t *testing.T) {
+	limiter := newRequestLimiter(0, 1)
+
+	stream := limiter.interceptor().WrapStreamingClient(func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		return &stubStreamingClientConn{}
+	})
+
+	conn := stream(context.Background(), connect.Spec{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := stream(ctx, connect.Spec{}).Send(nil); err == nil {
+		t.Fatal("expected error when no slot is available")
+	}
+
+	if err := conn.CloseResponse(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !conn.(*limitedStreamingClientConn).StreamingClientConn.(*stubStreamingClientConn).closed {
+		t.Fatal("expected the wrapped stream to be closed")
+	}
+
+	if _, err := limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("expected the slot to be released, got error: %s", err)
+	}
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/core/v1/corev1connect"
+	corev1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/core/v1"
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResource = &ProjectListResource{}
+var _ list.ListResourceWithConfigure = &ProjectListResource{}
+
+func NewProjectListResource() list.ListResource {
+	return &ProjectListResource{}
+}
+
+type ProjectListResource struct {
+	client corev1connect.ProjectServiceClient
+}
+
+type ProjectListResourceModel struct {
+	Name   types.String `tfsdk:"name"`
+	Region types.String `tfsdk:"region"`
+}
+
+func (r *ProjectListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project"
+}
+
+func (r *ProjectListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the Depot projects of the organization of the token.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only list the projects with this name.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthAtLeast(1),
+				},
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Only list the projects in this region.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *ProjectListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data := getProviderData(req.ProviderData, &resp.Diagnostics)
+
+	if data == nil {
+		return
+	}
+
+	r.client = data.ProjectClient
+}
+
+func (r *ProjectListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config ProjectListResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	projects, err := listProjects(ctx, r.client, config.Name, config.Region)
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list projects, got error: %s", err))
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for i, project := range projects {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = project.Name
+
+			result.Diagnostics.Append(result.Identity.Set(ctx, ProjectResourceIdentityModel{
+				Id: types.StringValue(project.ProjectId),
+			})...)
+
+			if req.IncludeResource {
+				var data ProjectResourceModel
+
+				data.setProject(project)
+
+				result.Diagnostics.Append(result.Resource.Set(ctx, &data)...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
+
+// listProjects lists the projects of the organization of the token, keeping
+// only the ones matching the name and region when they are set.
+func listProjects(ctx context.Context, client corev1connect.ProjectServiceClient, name types.String, region types.String) ([]*corev1.Project, error) {
+	response, err := client.ListProjects(ctx, &connect.Request[corev1.ListProjectsRequest]{
+		Msg: &corev1.ListProjectsRequest{},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	projects := []*corev1.Project{}
+
+	for _, project := range response.Msg.Projects {
+		if !name.IsNull() && project.Name != name.ValueString() {
+			continue
+		}
+
+		if !region.IsNull() && project.RegionId != region.ValueString() {
+			continue
+		}
+
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccProjectListResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			// Create the project to list
+			{
+				Config: testAccProjectResourceConfigDefault("todo-app-list"),
+			},
+			// Query testing
+			{
+				Query: true,
+				Config: `
+list "depot_project" "test" {
+  provider = depot
+
+  config {
+    name   = "todo-app-list"
+    region = "eu-central-1"
+  }
+}
+
+list "depot_project" "other_region" {
+  provider = depot
+
+  config {
+    name   = "todo-app-list"
+    region = "us-east-1"
+  }
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("depot_project.test", 1),
+					querycheck.ExpectLength("depot_project.other_region", 0),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/core/v1/corev1connect"
+	corev1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/core/v1"
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResource = &TrustPolicyListResource{}
+var _ list.ListResourceWithConfigure = &TrustPolicyListResource{}
+
+func NewTrustPolicyListResource() list.ListResource {
+	return &TrustPolicyListResource{}
+}
+
+type TrustPolicyListResource struct {
+	client corev1connect.ProjectServiceClient
+}
+
+type TrustPolicyListResourceModel struct {
+	ProjectId types.String `tfsdk:"project_id"`
+	Name      types.String `tfsdk:"name"`
+	Region    types.String `tfsdk:"region"`
+}
+
+func (r *TrustPolicyListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_trust_policy"
+}
+
+func (r *TrustPolicyListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the Depot trust policies of the projects of the organization of the token.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Only list the trust policies of this project.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only list the trust policies of the projects with this name.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthAtLeast(1),
+				},
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Only list the trust policies of the projects in this region.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *TrustPolicyListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data := getProviderData(req.ProviderData, &resp.Diagnostics)
+
+	if data == nil {
+		return
+	}
+
+	r.client = data.ProjectClient
+}
+
+func (r *TrustPolicyListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config TrustPolicyListResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var projectIds []string
+
+	if !config.ProjectId.IsNull() && config.Name.IsNull() && config.Region.IsNull() {
+		projectIds = []string{config.ProjectId.ValueString()}
+	} else {
+		projects, err := listProjects(ctx, r.client, config.Name, config.Region)
+
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to list projects, got error: %s", err))
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+
+		for _, project := range projects {
+			if config.ProjectId.IsNull() || project.ProjectId == config.ProjectId.ValueString() {
+				projectIds = append(projectIds, project.ProjectId)
+			}
+		}
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var count int64
+
+		for _, projectId := range projectIds {
+			response, err := r.client.ListTrustPolicies(ctx, &connect.Request[corev1.ListTrustPoliciesRequest]{
+				Msg: &corev1.ListTrustPoliciesRequest{
+					ProjectId: projectId,
+				},
+			})
+
+			if err != nil {
+				var diags diag.Diagnostics
+
+				diags.AddError("Client Error", fmt.Sprintf("Unable to list trust policies, got error: %s", err))
+
+				push(list.ListResult{Diagnostics: diags})
+				return
+			}
+
+			for _, trustPolicy := range response.Msg.TrustPolicies {
+				if req.Limit > 0 && count >= req.Limit {
+					return
+				}
+
+				count++
+
+				result := req.NewListResult(ctx)
+				result.DisplayName = trustPolicyDisplayName(projectId, trustPolicy)
+
+				result.Diagnostics.Append(result.Identity.Set(ctx, TrustPolicyResourceIdentityModel{
+					ProjectId: types.StringValue(projectId),
+					Id:        types.StringValue(trustPolicy.TrustPolicyId),
+				})...)
+
+				if req.IncludeResource {
+					var data TrustPolicyResourceModel
+
+					if err := data.setTrustPolicy(projectId, trustPolicy); err != nil {
+						result.Diagnostics.AddError("Invalid Response", fmt.Sprintf("Unable to read trust policy, got error: %s", err))
+					} else {
+						result.Diagnostics.Append(result.Resource.Set(ctx, &data)...)
+					}
+				}
+
+				if !push(result) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func trustPolicyDisplayName(projectId string, trustPolicy *corev1.TrustPolicy) string {
+	switch {
+	case trustPolicy.GetGithub() != nil:
+		return fmt.Sprintf("%s: github %s/%s", projectId, trustPolicy.GetGithub().RepositoryOwner, trustPolicy.GetGithub().Repository)
+	case trustPolicy.GetBuildkite() != nil:
+		return fmt.Sprintf("%s: buildkite %s/%s", projectId, trustPolicy.GetBuildkite().OrganizationSlug, trustPolicy.GetBuildkite().PipelineSlug)
+	case trustPolicy.GetCircleci() != nil:
+		return fmt.Sprintf("%s: circleci %s/%s", projectId, trustPolicy.GetCircleci().OrganizationUuid, trustPolicy.GetCircleci().ProjectUuid)
+	default:
+		return fmt.Sprintf("%s: %s", projectId, trustPolicy.TrustPolicyId)
+	}
+}
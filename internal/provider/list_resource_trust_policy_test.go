@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccTrustPolicyListResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			// Create the trust policy to list
+			{
+				Config: testAccTrustPolicyResourceConfigDefault(),
+			},
+			// Query testing
+			{
+				Query: true,
+				Config: `
+list "depot_trust_policy" "test" {
+  provider = depot
+
+  config {
+    project_id = "7f76t7vghb"
+  }
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLengthAtLeast("depot_trust_policy.test", 1),
+					querycheck.ExpectIdentity("depot_trust_policy.test", map[string]knownvalue.Check{
+						"project_id": knownvalue.StringExact("7f76t7vghb"),
+						"id":         knownvalue.NotNull(),
+					}),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	apiLogSubsystem = "api"
+	apiLogEnvVar    = "TF_LOG_PROVIDER_DEPOT_API"
+	redactedValue   = "[REDACTED]"
+)
+
+// newLoggingInterceptor logs every RPC made to the Depot API under the `api`
+// subsystem. The level of the subsystem is controlled by the
+// `TF_LOG_PROVIDER_DEPOT_API` environment variable.
+func newLoggingInterceptor(token string) connect.Interceptor {
+	return &loggingInterceptor{token: token}
+}
+
+type loggingInterceptor struct {
+	token string
+}
+
+// logContext returns the context logging the given procedure to the `api`
+// subsystem with the token masked.
+func (i *loggingInterceptor) logContext(ctx context.Context, procedure string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, apiLogSubsystem, tflog.WithLevelFromEnv(apiLogEnvVar))
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, apiLogSubsystem, "authorization", "token")
+
+	if i.token != "" {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, apiLogSubsystem, i.token)
+		ctx = tflog.SubsystemMaskMessageStrings(ctx, apiLogSubsystem, i.token)
+	}
+
+	return tflog.SubsystemSetField(ctx, apiLogSubsystem, "rpc_procedure", procedure)
+}
+
+func (i *loggingInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx = i.logContext(ctx, req.Spec().Procedure)
+
+		tflog.SubsystemDebug(ctx, apiLogSubsystem, "sending request", map[string]interface{}{
+			"rpc_request": redactMessage(req.Any()),
+		})
+
+		start := time.Now()
+		res, err := next(ctx, req)
+
+		fields := map[string]interface{}{
+			"rpc_duration_ms": time.Since(start).Milliseconds(),
+		}
+
+		// Failed requests are expected, e.g. when a refreshed resource no longer
+		// exists, so reporting them is left to the diagnostics.
+		if err != nil {
+			fields["rpc_status_code"] = connect.CodeOf(err).String()
+			fields["rpc_error"] = err.Error()
+
+			tflog.SubsystemDebug(ctx, apiLogSubsystem, "received error", fields)
+
+			return res, err
+		}
+
+		fields["rpc_status_code"] = "ok"
+		fields["rpc_response"] = redactMessage(res.Any())
+
+		tflog.SubsystemDebug(ctx, apiLogSubsystem, "received response", fields)
+
+		return res, err
+	}
+}
+
+func (i *loggingInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		ctx = i.logContext(ctx, spec.Procedure)
+
+		tflog.SubsystemDebug(ctx, apiLogSubsystem, "opening stream")
+
+		return &loggingStreamingClientConn{
+			StreamingClientConn: next(ctx, spec),
+			ctx:                 ctx,
+			start:               time.Now(),
+		}
+	}
+}
+
+func (i *loggingInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// loggingStreamingClientConn logs the messages of a stream and its status once
+// it is closed.
+type loggingStreamingClientConn struct {
+	connect.StreamingClientConn
+
+	ctx   context.Context
+	start time.Time
+	err   error
+}
+
+func (c *loggingStreamingClientConn) Send(msg any) error {
+	tflog.SubsystemDebug(c.ctx, apiLogSubsystem, "sending message", map[string]interface{}{
+		"rpc_request": redactMessage(msg),
+	})
+
+	return c.StreamingClientConn.Send(msg)
+}
+
+func (c *loggingStreamingClientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+
+	if err == nil {
+		tflog.SubsystemDebug(c.ctx, apiLogSubsystem, "received message", map[string]interface{}{
+			"rpc_response": redactMessage(msg),
+		})
+	} else if !errors.Is(err, io.EOF) {
+		c.err = err
+	}
+
+	return err
+}
+
+func (c *loggingStreamingClientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+
+	fields := map[string]interface{}{
+		"rpc_duration_ms": time.Since(c.start).Milliseconds(),
+	}
+
+	if c.err != nil {
+		fields["rpc_status_code"] = connect.CodeOf(c.err).String()
+		fields["rpc_error"] = c.err.Error()
+
+		tflog.SubsystemDebug(c.ctx, apiLogSubsystem, "stream failed", fields)
+
+		return err
+	}
+
+	fields["rpc_status_code"] = "ok"
+
+	tflog.SubsystemDebug(c.ctx, apiLogSubsystem, "closed stream", fields)
+
+	return err
+}
+
+// redactMessage returns the JSON representation of the given protobuf message
+// with the values of all sensitive fields replaced.
+func redactMessage(msg any) string {
+	m, ok := msg.(proto.Message)
+
+	if !ok || m == nil {
+		return ""
+	}
+
+	clone := proto.Clone(m)
+	redactFields(clone.ProtoReflect())
+
+	out, err := protojson.Marshal(clone)
+
+	if err != nil {
+		return ""
+	}
+
+	return string(out)
+}
+
+func redactFields(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() && isSensitiveField(fd.Name()):
+			m.Set(fd, protoreflect.ValueOfString(redactedValue))
+		case fd.Kind() == protoreflect.MessageKind && fd.IsList():
+			list := v.List()
+
+			for i := 0; i < list.Len(); i++ {
+				redactFields(list.Get(i).Message())
+			}
+		case fd.Kind() == protoreflect.MessageKind && !fd.IsMap():
+			redactFields(v.Message())
+		}
+
+		return true
+	})
+}
+
+func isSensitiveField(name protoreflect.Name) bool {
+	n := strings.ToLower(string(name))
+
+	for _, word := range []string{"token", "secret", "password"} {
+		if strings.Contains(n, word) {
+			return true
+		}
+	}
+
+	return n == "key" || strings.HasSuffix(n, "_key")
+}
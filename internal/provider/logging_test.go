@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	buildv1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/build/v1"
+	buildkitv1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/buildkit/v1"
+	corev1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/core/v1"
+)
+
+func TestRedactMessage(t *testing.T) {
+	msg := &buildv1.CreateBuildResponse{
+		BuildId:    "build-123",
+		BuildToken: "secret-build-token",
+	}
+
+	out := redactMessage(msg)
+
+	if strings.Contains(out, "secret-build-token") {
+		t.Fatalf("expected build token to be redacted, got: %s", out)
+	}
+
+	if !strings.Contains(out, "build-123") {
+		t.Fatalf("expected build id to be kept, got: %s", out)
+	}
+
+	if msg.BuildToken != "secret-build-token" {
+		t.Fatalf("expected original message to be untouched, got: %s", msg.BuildToken)
+	}
+}
+
+func TestRedactMessageNested(t *testing.T) {
+	msg := &buildkitv1.CertificatePair{
+		Cert: &buildkitv1.PublicCertificate{Cert: "public-cert"},
+		Key:  &buildkitv1.PrivateKey{Key: "private-key"},
+	}
+
+	out := redactMessage(msg)
+
+	if strings.Contains(out, "private-key") {
+		t.Fatalf("expected private key to be redacted, got: %s", out)
+	}
+
+	if !strings.Contains(out, "public-cert") {
+		t.Fatalf("expected certificate to be kept, got: %s", out)
+	}
+}
+
+func TestRedactMessageKeepsRegularFields(t *testing.T) {
+	msg := &corev1.ListProjectsResponse{
+		Projects: []*corev1.Project{
+			{ProjectId: "abc", Name: "todo-app", CachePolicy: &corev1.CachePolicy{KeepBytes: 1, KeepDays: 2}},
+		},
+	}
+
+	out := redactMessage(msg)
+
+	if strings.Contains(out, redactedValue) {
+		t.Fatalf("expected nothing to be redacted, got: %s", out)
+	}
+}
@@ -2,23 +2,40 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
 
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/build/v1/buildv1connect"
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/buildkit/v1/buildkitv1connect"
 	"buf.build/gen/go/depot/api/connectrpc/go/depot/core/v1/corev1connect"
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+const apiURL = "https://api.depot.dev"
+
 var (
 	envVarName          = "DEPOT_TOKEN"
 	errMissingAuthToken = "Required token could not be found. Please set the token using an input variable in the provider configuration block or by using the `" + envVarName + "` environment variable."
 )
 
 var _ provider.Provider = &DepotProvider{}
+var _ provider.ProviderWithListResources = &DepotProvider{}
+var _ provider.ProviderWithEphemeralResources = &DepotProvider{}
+var _ provider.ProviderWithFunctions = &DepotProvider{}
 
 type DepotProvider struct {
 	// version is set to the provider version on release, "dev" when the
@@ -27,8 +44,26 @@ type DepotProvider struct {
 	version string
 }
 
+// DepotProviderData is passed to the resources and data sources of the
+// provider when they are configured.
+type DepotProviderData struct {
+	ProjectClient  corev1connect.ProjectServiceClient
+	BuildClient    buildv1connect.BuildServiceClient
+	BuildKitClient buildkitv1connect.BuildKitServiceClient
+	// OrganizationId is the default organization of the resources, empty when
+	// the organization of the token should be used.
+	OrganizationId string
+	// StrictDriftCheck makes the resources check that the remote object still
+	// matches the state before updating or deleting it.
+	StrictDriftCheck bool
+}
+
 type DepotProviderModel struct {
-	Token types.String `tfsdk:"token"`
+	Token                 types.String  `tfsdk:"token"`
+	OrganizationId        types.String  `tfsdk:"organization_id"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	StrictDriftCheck      types.Bool    `tfsdk:"strict_drift_check"`
 }
 
 func (p *DepotProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -42,6 +77,32 @@ func (p *DepotProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 			"token": schema.StringAttribute{
 				MarkdownDescription: "The token used to authenticate with Depot.",
 				Optional:            true,
+				Sensitive:           true,
+			},
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the default organization of the resources. **Default** the organization of the token.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthAtLeast(1),
+				},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum number of requests per second sent to Depot, shared by all resources. **Default** no limit.",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0.1),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of requests to Depot in flight at the same time, shared by all resources. A streaming request holds its slot until it is closed. **Default** no limit.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"strict_drift_check": schema.BoolAttribute{
+				MarkdownDescription: "Whether to read projects again before updating or deleting them, failing when they were changed outside of Terraform since they were last read instead of overwriting the changes. **Default** `false`.",
+				Optional:            true,
 			},
 		},
 	}
@@ -74,15 +135,39 @@ func (p *DepotProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	client := corev1connect.NewProjectServiceClient(&http.Client{
+	limiter := newRequestLimiter(data.RequestsPerSecond.ValueFloat64(), data.MaxConcurrentRequests.ValueInt64())
+
+	tracingInterceptor, err := newTracingInterceptor()
+
+	if err != nil {
+		resp.Diagnostics.AddError("Tracing Error", fmt.Sprintf("Unable to configure tracing, got error: %s", err))
+		return
+	}
+
+	httpClient := &http.Client{
 		Transport: &authedTransport{
 			token:   token,
 			wrapped: http.DefaultTransport,
 		},
-	}, "https://api.depot.dev")
+	}
 
-	resp.DataSourceData = &client
-	resp.ResourceData = &client
+	interceptors := connect.WithInterceptors(tracingInterceptor, limiter.interceptor(), newLoggingInterceptor(token))
+	cache := newTrustPolicyCache(trustPolicyCacheTTL)
+
+	providerData := &DepotProviderData{
+		// Trust policies are read by listing all the trust policies of the project,
+		// so the list is cached to avoid listing them once per trust policy.
+		ProjectClient:    newCachingProjectServiceClient(corev1connect.NewProjectServiceClient(httpClient, apiURL, interceptors), cache),
+		BuildClient:      buildv1connect.NewBuildServiceClient(httpClient, apiURL, interceptors),
+		BuildKitClient:   buildkitv1connect.NewBuildKitServiceClient(httpClient, apiURL, interceptors),
+		OrganizationId:   data.OrganizationId.ValueString(),
+		StrictDriftCheck: data.StrictDriftCheck.ValueBool(),
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+	resp.ListResourceData = providerData
+	resp.EphemeralResourceData = providerData
 }
 
 func (p *DepotProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -96,6 +181,27 @@ func (p *DepotProvider) DataSources(ctx context.Context) []func() datasource.Dat
 	return []func() datasource.DataSource{}
 }
 
+func (p *DepotProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewBuildKitConnectionEphemeralResource,
+	}
+}
+
+func (p *DepotProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewProjectListResource,
+		NewTrustPolicyListResource,
+	}
+}
+
+func (p *DepotProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewTrustPolicyImportIdFunction,
+		NewParseTrustPolicyIdFunction,
+		NewCacheBytesFunction,
+	}
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &DepotProvider{
@@ -110,7 +216,27 @@ type authedTransport struct {
 }
 
 func (t *authedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Set("Authorization", "Bearer "+t.token)
+	// Requests made on behalf of a build are authenticated with the build token.
+	if req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
 
 	return t.wrapped.RoundTrip(req)
 }
+
+// getProviderData returns the provider data given to the Configure method of a
+// resource or data source, adding an error diagnostic when it has the wrong type.
+func getProviderData(providerData any, diags *diag.Diagnostics) *DepotProviderData {
+	data, ok := providerData.(*DepotProviderData)
+
+	if !ok {
+		diags.AddError(
+			"Unexpected Configure Type",
+			fmt.Sprintf("Expected *provider.DepotProviderData, got: %T. Please report this issue to the provider developers.", providerData),
+		)
+
+		return nil
+	}
+
+	return data
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
@@ -21,3 +22,17 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("DEPOT_TOKEN must be set for acceptance tests")
 	}
 }
+
+func TestGetProviderData(t *testing.T) {
+	var diags diag.Diagnostics
+
+	expected := &DepotProviderData{OrganizationId: "org"}
+
+	if actual := getProviderData(expected, &diags); actual != expected || diags.HasError() {
+		t.Fatalf("expected provider data to be returned, got: %v %v", actual, diags)
+	}
+
+	if actual := getProviderData("wrong", &diags); actual != nil || !diags.HasError() {
+		t.Fatalf("expected error diagnostic for wrong type, got: %v %v", actual, diags)
+	}
+}
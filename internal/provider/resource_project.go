@@ -2,15 +2,20 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"buf.build/gen/go/depot/api/connectrpc/go/depot/core/v1/corev1connect"
 	corev1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/core/v1"
 	"connectrpc.com/connect"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectdefault"
@@ -24,6 +29,8 @@ import (
 
 var _ resource.Resource = &ProjectResource{}
 var _ resource.ResourceWithImportState = &ProjectResource{}
+var _ resource.ResourceWithIdentity = &ProjectResource{}
+var _ resource.ResourceWithUpgradeState = &ProjectResource{}
 
 const sizeGB = 1024 * 1024 * 1024
 
@@ -32,7 +39,9 @@ func NewProjectResource() resource.Resource {
 }
 
 type ProjectResource struct {
-	client corev1connect.ProjectServiceClient
+	client           corev1connect.ProjectServiceClient
+	organizationId   string
+	strictDriftCheck bool
 }
 
 type ProjectResourceCacheModel struct {
@@ -51,6 +60,11 @@ type ProjectResourceModel struct {
 	Name           types.String `tfsdk:"name"`
 	Region         types.String `tfsdk:"region"`
 	Cache          types.Object `tfsdk:"cache"`
+	CreatedAt      types.String `tfsdk:"created_at"`
+}
+
+type ProjectResourceIdentityModel struct {
+	Id types.String `tfsdk:"id"`
 }
 
 func (r *ProjectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -60,6 +74,7 @@ func (r *ProjectResource) Metadata(ctx context.Context, req resource.MetadataReq
 func (r *ProjectResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Depot project.",
+		Version:             1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				MarkdownDescription: "Identifier of the project.",
@@ -69,7 +84,7 @@ func (r *ProjectResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"organization_id": schema.StringAttribute{
-				MarkdownDescription: "Identifier of the organization.",
+				MarkdownDescription: "Identifier of the organization. **Default** the organization of the provider.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
@@ -124,31 +139,107 @@ func (r *ProjectResource) Schema(ctx context.Context, req resource.SchemaRequest
 					},
 				},
 			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Time at which the project was created, in RFC 3339 format.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ProjectResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				Description:       "Identifier of the project.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+// projectResourceModelV0 is the state of the project before the schema was
+// versioned.
+type projectResourceModelV0 struct {
+	Id             string `json:"id"`
+	OrganizationId string `json:"organization_id"`
+	Name           string `json:"name"`
+	Region         string `json:"region"`
+	Cache          *struct {
+		Size   *int64 `json:"size"`
+		Expiry *int64 `json:"expiry"`
+	} `json:"cache"`
+}
+
+func (r *ProjectResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			StateUpgrader: upgradeProjectStateV0,
 		},
 	}
 }
 
+func upgradeProjectStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior projectResourceModelV0
+
+	if err := json.Unmarshal(req.RawState.JSON, &prior); err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to read version 0 of the project state, got error: %s", err))
+		return
+	}
+
+	size, expiry := int64(50), int64(14)
+
+	if prior.Cache != nil && prior.Cache.Size != nil {
+		size = *prior.Cache.Size
+	}
+
+	if prior.Cache != nil && prior.Cache.Expiry != nil {
+		expiry = *prior.Cache.Expiry
+	}
+
+	data := ProjectResourceModel{
+		Id:             types.StringValue(prior.Id),
+		OrganizationId: types.StringValue(prior.OrganizationId),
+		Name:           types.StringValue(prior.Name),
+		Region:         types.StringValue(prior.Region),
+		Cache: types.ObjectValueMust(
+			cacheAttrTypes,
+			map[string]attr.Value{
+				"size":   types.Int64Value(size),
+				"expiry": types.Int64Value(expiry),
+			},
+		),
+		// Refreshed on the next read.
+		CreatedAt: types.StringNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
 func (r *ProjectResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*corev1connect.ProjectServiceClient)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
+	data := getProviderData(req.ProviderData, &resp.Diagnostics)
 
+	if data == nil {
 		return
 	}
 
-	r.client = *client
+	r.client = data.ProjectClient
+	r.organizationId = data.OrganizationId
+	r.strictDriftCheck = data.StrictDriftCheck
 }
 
 func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startResourceSpan(ctx, "depot_project", "Create")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data *ProjectResourceModel
 	var cacheData *ProjectResourceCacheModel
 
@@ -173,8 +264,10 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 		},
 	}
 
-	if !data.OrganizationId.IsNull() {
+	if !data.OrganizationId.IsNull() && !data.OrganizationId.IsUnknown() {
 		input.OrganizationId = data.OrganizationId.ValueStringPointer()
+	} else if r.organizationId != "" {
+		input.OrganizationId = &r.organizationId
 	}
 
 	response, err := r.client.CreateProject(ctx, &connect.Request[corev1.CreateProjectRequest]{
@@ -186,25 +279,20 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	setSpanResourceId(span, response.Msg.Project.ProjectId)
+
 	tflog.Trace(ctx, "created a project")
 
-	data.Id = types.StringValue(response.Msg.Project.ProjectId)
-	data.OrganizationId = types.StringValue(response.Msg.Project.OrganizationId)
-	data.Name = types.StringValue(response.Msg.Project.Name)
-	data.Region = types.StringValue(response.Msg.Project.RegionId)
-
-	data.Cache = types.ObjectValueMust(
-		cacheAttrTypes,
-		map[string]attr.Value{
-			"size":   types.Int64Value(response.Msg.Project.CachePolicy.KeepBytes / sizeGB),
-			"expiry": types.Int64Value(int64(response.Msg.Project.CachePolicy.KeepDays)),
-		},
-	)
+	data.setProject(response.Msg.Project)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ProjectResourceIdentityModel{Id: data.Id})...)
 }
 
 func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startResourceSpan(ctx, "depot_project", "Read")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data *ProjectResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -213,6 +301,8 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	setSpanResourceId(span, data.Id.ValueString())
+
 	response, err := r.client.GetProject(ctx, &connect.Request[corev1.GetProjectRequest]{
 		Msg: &corev1.GetProjectRequest{
 			ProjectId: data.Id.ValueString(),
@@ -224,46 +314,63 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	data.Id = types.StringValue(response.Msg.Project.ProjectId)
-	data.OrganizationId = types.StringValue(response.Msg.Project.OrganizationId)
-	data.Name = types.StringValue(response.Msg.Project.Name)
-	data.Region = types.StringValue(response.Msg.Project.RegionId)
-
-	data.Cache = types.ObjectValueMust(
-		cacheAttrTypes,
-		map[string]attr.Value{
-			"size":   types.Int64Value(response.Msg.Project.CachePolicy.KeepBytes / sizeGB),
-			"expiry": types.Int64Value(int64(response.Msg.Project.CachePolicy.KeepDays)),
-		},
-	)
+	data.setProject(response.Msg.Project)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ProjectResourceIdentityModel{Id: data.Id})...)
 }
 
 func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startResourceSpan(ctx, "depot_project", "Update")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data *ProjectResourceModel
-	var cacheData *ProjectResourceCacheModel
+	var state *ProjectResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(data.Cache.As(ctx, &cacheData, basetypes.ObjectAsOptions{})...)
+	setSpanResourceId(span, data.Id.ValueString())
 
-	if resp.Diagnostics.HasError() {
-		return
+	if r.strictDriftCheck {
+		r.checkDrift(ctx, state, &resp.Diagnostics)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
+	// Only the changed fields are sent so that concurrent changes to the other
+	// fields of the project are not overwritten.
 	input := corev1.UpdateProjectRequest{
 		ProjectId: data.Id.ValueString(),
-		Name:      data.Name.ValueStringPointer(),
-		RegionId:  data.Region.ValueStringPointer(),
-		CachePolicy: &corev1.CachePolicy{
+	}
+
+	if !data.Name.Equal(state.Name) {
+		input.Name = data.Name.ValueStringPointer()
+	}
+
+	if !data.Region.Equal(state.Region) {
+		input.RegionId = data.Region.ValueStringPointer()
+	}
+
+	if !data.Cache.Equal(state.Cache) {
+		var cacheData *ProjectResourceCacheModel
+
+		resp.Diagnostics.Append(data.Cache.As(ctx, &cacheData, basetypes.ObjectAsOptions{})...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		input.CachePolicy = &corev1.CachePolicy{
 			KeepBytes: cacheData.Size.ValueInt64() * sizeGB,
 			KeepDays:  int32(cacheData.Expiry.ValueInt64()),
-		},
+		}
 	}
 
 	response, err := r.client.UpdateProject(ctx, &connect.Request[corev1.UpdateProjectRequest]{
@@ -277,23 +384,35 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	tflog.Trace(ctx, "updated a project")
 
-	data.Id = types.StringValue(response.Msg.Project.ProjectId)
-	data.OrganizationId = types.StringValue(response.Msg.Project.OrganizationId)
-	data.Name = types.StringValue(response.Msg.Project.Name)
-	data.Region = types.StringValue(response.Msg.Project.RegionId)
+	planned := *data
 
-	data.Cache = types.ObjectValueMust(
-		cacheAttrTypes,
-		map[string]attr.Value{
-			"size":   types.Int64Value(response.Msg.Project.CachePolicy.KeepBytes / sizeGB),
-			"expiry": types.Int64Value(int64(response.Msg.Project.CachePolicy.KeepDays)),
-		},
-	)
+	data.setProject(response.Msg.Project)
+
+	// The fields which were not sent keep their planned values, so that changes
+	// made outside of Terraform since the plan show up as drift on the next
+	// refresh instead of failing the apply.
+	data.OrganizationId = planned.OrganizationId
+
+	if input.Name == nil {
+		data.Name = planned.Name
+	}
+
+	if input.RegionId == nil {
+		data.Region = planned.Region
+	}
+
+	if input.CachePolicy == nil {
+		data.Cache = planned.Cache
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ProjectResourceIdentityModel{Id: data.Id})...)
 }
 
 func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startResourceSpan(ctx, "depot_project", "Delete")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data *ProjectResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -302,6 +421,16 @@ func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	setSpanResourceId(span, data.Id.ValueString())
+
+	if r.strictDriftCheck {
+		r.checkDrift(ctx, data, &resp.Diagnostics)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	_, err := r.client.DeleteProject(ctx, &connect.Request[corev1.DeleteProjectRequest]{
 		Msg: &corev1.DeleteProjectRequest{
 			ProjectId: data.Id.ValueString(),
@@ -316,6 +445,75 @@ func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest
 	tflog.Trace(ctx, "deleted a project")
 }
 
+// checkDrift reads the project and adds an error diagnostic naming the
+// attributes which no longer match the state, i.e. which were changed outside
+// of Terraform since the project was last read.
+func (r *ProjectResource) checkDrift(ctx context.Context, state *ProjectResourceModel, diags *diag.Diagnostics) {
+	response, err := r.client.GetProject(ctx, &connect.Request[corev1.GetProjectRequest]{
+		Msg: &corev1.GetProjectRequest{
+			ProjectId: state.Id.ValueString(),
+		},
+	})
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		return
+	}
+
+	var remote ProjectResourceModel
+
+	remote.setProject(response.Msg.Project)
+
+	changed := []string{}
+
+	if !remote.OrganizationId.Equal(state.OrganizationId) {
+		changed = append(changed, "organization_id")
+	}
+
+	if !remote.Name.Equal(state.Name) {
+		changed = append(changed, "name")
+	}
+
+	if !remote.Region.Equal(state.Region) {
+		changed = append(changed, "region")
+	}
+
+	for _, name := range []string{"size", "expiry"} {
+		if !remote.Cache.Attributes()[name].Equal(state.Cache.Attributes()[name]) {
+			changed = append(changed, "cache."+name)
+		}
+	}
+
+	if len(changed) > 0 {
+		diags.AddError(
+			"Project Changed",
+			fmt.Sprintf("The project was changed outside of Terraform since it was last read, these attributes no longer match the state: %s. Refresh the state and plan again to apply the configuration on top of these changes.", strings.Join(changed, ", ")),
+		)
+	}
+}
+
 func (r *ProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
+}
+
+// setProject sets the attributes of the model from the project returned by Depot.
+func (data *ProjectResourceModel) setProject(project *corev1.Project) {
+	data.Id = types.StringValue(project.ProjectId)
+	data.OrganizationId = types.StringValue(project.OrganizationId)
+	data.Name = types.StringValue(project.Name)
+	data.Region = types.StringValue(project.RegionId)
+
+	data.Cache = types.ObjectValueMust(
+		cacheAttrTypes,
+		map[string]attr.Value{
+			"size":   types.Int64Value(project.CachePolicy.GetKeepBytes() / sizeGB),
+			"expiry": types.Int64Value(int64(project.CachePolicy.GetKeepDays())),
+		},
+	)
+
+	data.CreatedAt = types.StringNull()
+
+	if project.CreatedAt != nil {
+		data.CreatedAt = types.StringValue(project.CreatedAt.AsTime().UTC().Format(time.RFC3339))
+	}
 }
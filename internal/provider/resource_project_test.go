@@ -1,10 +1,25 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
+	"buf.build/gen/go/depot/api/connectrpc/go/depot/core/v1/corev1connect"
+	corev1 "buf.build/gen/go/depot/api/protocolbuffers/go/depot/core/v1"
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestAccProjectResourceDefault(t *testing.T) {
@@ -17,6 +32,7 @@ func TestAccProjectResourceDefault(t *testing.T) {
 				Config: testAccProjectResourceConfigDefault("todo-app"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("depot_project.test", "id"),
+					resource.TestCheckResourceAttrSet("depot_project.test", "created_at"),
 					resource.TestCheckResourceAttr("depot_project.test", "name", "todo-app"),
 					resource.TestCheckResourceAttr("depot_project.test", "region", "eu-central-1"),
 					resource.TestCheckResourceAttr("depot_project.test", "cache.size", "50"),
@@ -34,6 +50,7 @@ func TestAccProjectResourceDefault(t *testing.T) {
 				Config: testAccProjectResourceConfigDefault("todo-app"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("depot_project.test", "id"),
+					resource.TestCheckResourceAttrSet("depot_project.test", "created_at"),
 					resource.TestCheckResourceAttr("depot_project.test", "name", "todo-app"),
 					resource.TestCheckResourceAttr("depot_project.test", "region", "eu-central-1"),
 					resource.TestCheckResourceAttr("depot_project.test", "cache.size", "50"),
@@ -45,6 +62,7 @@ func TestAccProjectResourceDefault(t *testing.T) {
 				Config: testAccProjectResourceConfigNonDefault("nue-todo-app"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("depot_project.test", "id"),
+					resource.TestCheckResourceAttrSet("depot_project.test", "created_at"),
 					resource.TestCheckResourceAttr("depot_project.test", "name", "nue-todo-app"),
 					resource.TestCheckResourceAttr("depot_project.test", "region", "us-east-1"),
 					resource.TestCheckResourceAttr("depot_project.test", "cache.size", "25"),
@@ -72,6 +90,7 @@ func TestAccProjectResourceNonDefault(t *testing.T) {
 				Config: testAccProjectResourceConfigNonDefault("todo-app"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("depot_project.test", "id"),
+					resource.TestCheckResourceAttrSet("depot_project.test", "created_at"),
 					resource.TestCheckResourceAttr("depot_project.test", "name", "todo-app"),
 					resource.TestCheckResourceAttr("depot_project.test", "region", "us-east-1"),
 					resource.TestCheckResourceAttr("depot_project.test", "cache.size", "25"),
@@ -89,6 +108,7 @@ func TestAccProjectResourceNonDefault(t *testing.T) {
 				Config: testAccProjectResourceConfigNonDefault("todo-app"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("depot_project.test", "id"),
+					resource.TestCheckResourceAttrSet("depot_project.test", "created_at"),
 					resource.TestCheckResourceAttr("depot_project.test", "name", "todo-app"),
 					resource.TestCheckResourceAttr("depot_project.test", "region", "us-east-1"),
 					resource.TestCheckResourceAttr("depot_project.test", "cache.size", "25"),
@@ -100,6 +120,7 @@ func TestAccProjectResourceNonDefault(t *testing.T) {
 				Config: testAccProjectResourceConfigDefault("nue-todo-app"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("depot_project.test", "id"),
+					resource.TestCheckResourceAttrSet("depot_project.test", "created_at"),
 					resource.TestCheckResourceAttr("depot_project.test", "name", "nue-todo-app"),
 					resource.TestCheckResourceAttr("depot_project.test", "region", "eu-central-1"),
 					resource.TestCheckResourceAttr("depot_project.test", "cache.size", "50"),
@@ -117,6 +138,519 @@ func TestAccProjectResourceNonDefault(t *testing.T) {
 	})
 }
 
+func TestAccProjectResourceIdentity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccProjectResourceConfigDefault("todo-app"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("depot_project.test", map[string]knownvalue.Check{
+						"id": knownvalue.NotNull(),
+					}),
+				},
+			},
+			// ImportState with identity testing
+			{
+				ResourceName:    "depot_project.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestProjectResourceUpgradeStateV0(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		size   int64
+		expiry int64
+	}{
+		{
+			name:   "cache",
+			json:   `{"id":"abc","organization_id":"org","name":"todo-app","region":"eu-central-1","cache":{"size":100,"expiry":7}}`,
+			size:   100,
+			expiry: 7,
+		},
+		{
+			name:   "no cache",
+			json:   `{"id":"abc","organization_id":"org","name":"todo-app","region":"eu-central-1","cache":null}`,
+			size:   50,
+			expiry: 14,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &ProjectResource{}
+
+			var schemaResp fwresource.SchemaResponse
+
+			r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+			resp := fwresource.UpgradeStateResponse{
+				State: tfsdk.State{
+					Schema: schemaResp.Schema,
+					Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+				},
+			}
+
+			r.UpgradeState(ctx)[0].StateUpgrader(ctx, fwresource.UpgradeStateRequest{
+				RawState: &tfprotov6.RawState{JSON: []byte(test.json)},
+			}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data ProjectResourceModel
+
+			resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if data.Id.ValueString() != "abc" || data.OrganizationId.ValueString() != "org" || data.Name.ValueString() != "todo-app" || data.Region.ValueString() != "eu-central-1" {
+				t.Errorf("unexpected project: %v", data)
+			}
+
+			size := data.Cache.Attributes()["size"].(types.Int64).ValueInt64()
+			expiry := data.Cache.Attributes()["expiry"].(types.Int64).ValueInt64()
+
+			if size != test.size || expiry != test.expiry {
+				t.Errorf("got cache %d/%d, want %d/%d", size, expiry, test.size, test.expiry)
+			}
+		})
+	}
+}
+
+// fakeProjectServiceClient serves a single project from memory, recording the
+// update requests it receives.
+type fakeProjectServiceClient struct {
+	corev1connect.ProjectServiceClient
+
+	project *corev1.Project
+	creates []*corev1.CreateProjectRequest
+	updates []*corev1.UpdateProjectRequest
+	deletes []*corev1.DeleteProjectRequest
+}
+
+func (c *fakeProjectServiceClient) CreateProject(ctx context.Context, req *connect.Request[corev1.CreateProjectRequest]) (*connect.Response[corev1.CreateProjectResponse], error) {
+	c.creates = append(c.creates, req.Msg)
+
+	c.project = testProject()
+	c.project.Name = req.Msg.Name
+	c.project.RegionId = req.Msg.RegionId
+	c.project.CachePolicy = req.Msg.CachePolicy
+
+	// Projects are created in the organization of the token by default.
+	c.project.OrganizationId = req.Msg.GetOrganizationId()
+
+	if req.Msg.OrganizationId == nil {
+		c.project.OrganizationId = "token-org"
+	}
+
+	return connect.NewResponse(&corev1.CreateProjectResponse{Project: c.project}), nil
+}
+
+func (c *fakeProjectServiceClient) GetProject(ctx context.Context, req *connect.Request[corev1.GetProjectRequest]) (*connect.Response[corev1.GetProjectResponse], error) {
+	return connect.NewResponse(&corev1.GetProjectResponse{Project: c.project}), nil
+}
+
+func (c *fakeProjectServiceClient) UpdateProject(ctx context.Context, req *connect.Request[corev1.UpdateProjectRequest]) (*connect.Response[corev1.UpdateProjectResponse], error) {
+	c.updates = append(c.updates, req.Msg)
+
+	if req.Msg.Name != nil {
+		c.project.Name = *req.Msg.Name
+	}
+
+	if req.Msg.RegionId != nil {
+		c.project.RegionId = *req.Msg.RegionId
+	}
+
+	if req.Msg.CachePolicy != nil {
+		c.project.CachePolicy = req.Msg.CachePolicy
+	}
+
+	return connect.NewResponse(&corev1.UpdateProjectResponse{Project: c.project}), nil
+}
+
+func (c *fakeProjectServiceClient) DeleteProject(ctx context.Context, req *connect.Request[corev1.DeleteProjectRequest]) (*connect.Response[corev1.DeleteProjectResponse], error) {
+	c.deletes = append(c.deletes, req.Msg)
+
+	return connect.NewResponse(&corev1.DeleteProjectResponse{}), nil
+}
+
+func testProject() *corev1.Project {
+	return &corev1.Project{
+		ProjectId:      "abc",
+		OrganizationId: "org",
+		Name:           "todo-app",
+		RegionId:       "eu-central-1",
+		CachePolicy: &corev1.CachePolicy{
+			KeepBytes: 50 * sizeGB,
+			KeepDays:  14,
+		},
+	}
+}
+
+func testProjectModel(name string, size int64, expiry int64) ProjectResourceModel {
+	var data ProjectResourceModel
+
+	data.setProject(testProject())
+
+	data.Name = types.StringValue(name)
+	data.Cache = types.ObjectValueMust(
+		cacheAttrTypes,
+		map[string]attr.Value{
+			"size":   types.Int64Value(size),
+			"expiry": types.Int64Value(expiry),
+		},
+	)
+
+	return data
+}
+
+func testProjectUpdate(t *testing.T, r *ProjectResource, state ProjectResourceModel, plan ProjectResourceModel) fwresource.UpdateResponse {
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	var identitySchemaResp fwresource.IdentitySchemaResponse
+
+	r.IdentitySchema(ctx, fwresource.IdentitySchemaRequest{}, &identitySchemaResp)
+
+	emptyValue := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+
+	req := fwresource.UpdateRequest{
+		Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: emptyValue},
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: emptyValue},
+	}
+
+	diags := req.Plan.Set(ctx, &plan)
+	diags.Append(req.State.Set(ctx, &state)...)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	resp := fwresource.UpdateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: emptyValue},
+		Identity: &tfsdk.ResourceIdentity{
+			Schema: identitySchemaResp.IdentitySchema,
+			Raw:    tftypes.NewValue(identitySchemaResp.IdentitySchema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	r.Update(ctx, req, &resp)
+
+	return resp
+}
+
+func TestProjectResourceCreateOrganization(t *testing.T) {
+	tests := []struct {
+		name             string
+		organizationId   types.String
+		providerDefault  string
+		wantRequest      *string
+		wantOrganization string
+	}{
+		{
+			name:             "configured",
+			organizationId:   types.StringValue("resource-org"),
+			providerDefault:  "provider-org",
+			wantRequest:      proto.String("resource-org"),
+			wantOrganization: "resource-org",
+		},
+		{
+			name:             "provider default",
+			organizationId:   types.StringUnknown(),
+			providerDefault:  "provider-org",
+			wantRequest:      proto.String("provider-org"),
+			wantOrganization: "provider-org",
+		},
+		{
+			name:             "unset",
+			organizationId:   types.StringUnknown(),
+			wantOrganization: "token-org",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := &fakeProjectServiceClient{}
+			r := &ProjectResource{client: client, organizationId: test.providerDefault}
+
+			var schemaResp fwresource.SchemaResponse
+
+			r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+			var identitySchemaResp fwresource.IdentitySchemaResponse
+
+			r.IdentitySchema(ctx, fwresource.IdentitySchemaRequest{}, &identitySchemaResp)
+
+			emptyValue := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+
+			plan := testProjectModel("todo-app", 50, 14)
+			plan.Id = types.StringUnknown()
+			plan.OrganizationId = test.organizationId
+			plan.CreatedAt = types.StringUnknown()
+
+			req := fwresource.CreateRequest{
+				Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: emptyValue},
+			}
+
+			if diags := req.Plan.Set(ctx, &plan); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			resp := fwresource.CreateResponse{
+				State: tfsdk.State{Schema: schemaResp.Schema, Raw: emptyValue},
+				Identity: &tfsdk.ResourceIdentity{
+					Schema: identitySchemaResp.IdentitySchema,
+					Raw:    tftypes.NewValue(identitySchemaResp.IdentitySchema.Type().TerraformType(ctx), nil),
+				},
+			}
+
+			r.Create(ctx, req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if len(client.creates) != 1 {
+				t.Fatalf("expected 1 create request, got %d", len(client.creates))
+			}
+
+			if got := client.creates[0].OrganizationId; (got == nil) != (test.wantRequest == nil) || (got != nil && *got != *test.wantRequest) {
+				t.Errorf("got organization %v in the create request, want %v", got, test.wantRequest)
+			}
+
+			var data ProjectResourceModel
+
+			resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+
+			if data.OrganizationId.ValueString() != test.wantOrganization {
+				t.Errorf("got organization %q in the state, want %q", data.OrganizationId.ValueString(), test.wantOrganization)
+			}
+		})
+	}
+}
+
+func TestProjectResourceUpdateSendsChangedFields(t *testing.T) {
+	tests := []struct {
+		name string
+		plan ProjectResourceModel
+		want *corev1.UpdateProjectRequest
+	}{
+		{
+			name: "name",
+			plan: testProjectModel("other-app", 50, 14),
+			want: &corev1.UpdateProjectRequest{
+				ProjectId: "abc",
+				Name:      proto.String("other-app"),
+			},
+		},
+		{
+			name: "cache",
+			plan: testProjectModel("todo-app", 100, 14),
+			want: &corev1.UpdateProjectRequest{
+				ProjectId: "abc",
+				CachePolicy: &corev1.CachePolicy{
+					KeepBytes: 100 * sizeGB,
+					KeepDays:  14,
+				},
+			},
+		},
+		{
+			name: "name and cache",
+			plan: testProjectModel("other-app", 50, 7),
+			want: &corev1.UpdateProjectRequest{
+				ProjectId: "abc",
+				Name:      proto.String("other-app"),
+				CachePolicy: &corev1.CachePolicy{
+					KeepBytes: 50 * sizeGB,
+					KeepDays:  7,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fakeProjectServiceClient{project: testProject()}
+			r := &ProjectResource{client: client}
+
+			resp := testProjectUpdate(t, r, testProjectModel("todo-app", 50, 14), test.plan)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if len(client.updates) != 1 {
+				t.Fatalf("expected 1 update request, got %d", len(client.updates))
+			}
+
+			if !proto.Equal(client.updates[0], test.want) {
+				t.Errorf("got update request %v, want %v", client.updates[0], test.want)
+			}
+
+			var data ProjectResourceModel
+
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if !data.Name.Equal(test.plan.Name) || !data.Cache.Equal(test.plan.Cache) {
+				t.Errorf("got state %v, want %v", data, test.plan)
+			}
+		})
+	}
+}
+
+func TestProjectResourceUpdateKeepsUnsentFields(t *testing.T) {
+	client := &fakeProjectServiceClient{project: testProject()}
+	r := &ProjectResource{client: client}
+
+	state := testProjectModel("todo-app", 50, 14)
+	plan := testProjectModel("todo-app", 100, 14)
+
+	// The project is renamed in the dashboard after the plan.
+	client.project.Name = "dashboard-app"
+
+	resp := testProjectUpdate(t, r, state, plan)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(client.updates) != 1 || client.updates[0].Name != nil {
+		t.Fatalf("expected 1 update request without the name, got %v", client.updates)
+	}
+
+	var data ProjectResourceModel
+
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if !data.Name.Equal(plan.Name) || !data.Cache.Equal(plan.Cache) {
+		t.Errorf("got state %v, want the planned values %v", data, plan)
+	}
+
+	if client.project.Name != "dashboard-app" {
+		t.Errorf("expected the dashboard change to be kept, got name %q", client.project.Name)
+	}
+}
+
+func TestProjectResourceUpdateStrictDriftCheck(t *testing.T) {
+	client := &fakeProjectServiceClient{project: testProject()}
+	r := &ProjectResource{client: client, strictDriftCheck: true}
+
+	resp := testProjectUpdate(t, r, testProjectModel("todo-app", 50, 14), testProjectModel("other-app", 50, 14))
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(client.updates) != 1 {
+		t.Fatalf("expected 1 update request, got %d", len(client.updates))
+	}
+
+	client.project.Name = "dashboard-app"
+	client.project.CachePolicy.KeepDays = 7
+
+	resp = testProjectUpdate(t, r, testProjectModel("other-app", 50, 14), testProjectModel("other-app", 100, 14))
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error diagnostic")
+	}
+
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "name, cache.expiry.") {
+		t.Errorf("expected the changed attributes in the error, got %q", detail)
+	}
+
+	if len(client.updates) != 1 {
+		t.Errorf("expected no update request after drift, got %d", len(client.updates)-1)
+	}
+}
+
+func TestProjectResourceDeleteStrictDriftCheck(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeProjectServiceClient{project: testProject()}
+	r := &ProjectResource{client: client, strictDriftCheck: true}
+
+	var schemaResp fwresource.SchemaResponse
+
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	emptyValue := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+
+	deleteProject := func() fwresource.DeleteResponse {
+		req := fwresource.DeleteRequest{
+			State: tfsdk.State{Schema: schemaResp.Schema, Raw: emptyValue},
+		}
+
+		data := testProjectModel("todo-app", 50, 14)
+
+		if diags := req.State.Set(ctx, &data); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+
+		resp := fwresource.DeleteResponse{
+			State: tfsdk.State{Schema: schemaResp.Schema, Raw: emptyValue},
+		}
+
+		r.Delete(ctx, req, &resp)
+
+		return resp
+	}
+
+	client.project.CachePolicy.KeepBytes = 100 * sizeGB
+
+	resp := deleteProject()
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error diagnostic")
+	}
+
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "cache.size.") {
+		t.Errorf("expected the changed attributes in the error, got %q", detail)
+	}
+
+	if len(client.deletes) != 0 {
+		t.Fatalf("expected no delete request after drift, got %d", len(client.deletes))
+	}
+
+	client.project = testProject()
+
+	resp = deleteProject()
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(client.deletes) != 1 {
+		t.Fatalf("expected 1 delete request, got %d", len(client.deletes))
+	}
+}
+
 func testAccProjectResourceConfigDefault(name string) string {
 	return fmt.Sprintf(`
 resource "depot_project" "test" {
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -25,6 +27,8 @@ import (
 
 var _ resource.Resource = &TrustPolicyResource{}
 var _ resource.ResourceWithImportState = &TrustPolicyResource{}
+var _ resource.ResourceWithIdentity = &TrustPolicyResource{}
+var _ resource.ResourceWithUpgradeState = &TrustPolicyResource{}
 
 func NewTrustPolicyResource() resource.Resource {
 	return &TrustPolicyResource{}
@@ -72,6 +76,11 @@ type TrustPolicyResourceModel struct {
 	Circleci  types.Object `tfsdk:"circleci"`
 }
 
+type TrustPolicyResourceIdentityModel struct {
+	ProjectId types.String `tfsdk:"project_id"`
+	Id        types.String `tfsdk:"id"`
+}
+
 func (r *TrustPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_trust_policy"
 }
@@ -79,6 +88,7 @@ func (r *TrustPolicyResource) Metadata(ctx context.Context, req resource.Metadat
 func (r *TrustPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Depot trust policy.",
+		Version:             1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				MarkdownDescription: "Identifier of the trust policy.",
@@ -173,27 +183,116 @@ func (r *TrustPolicyResource) Schema(ctx context.Context, req resource.SchemaReq
 	}
 }
 
+func (r *TrustPolicyResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"project_id": identityschema.StringAttribute{
+				Description:       "Identifier of the project for the trust policy.",
+				RequiredForImport: true,
+			},
+			"id": identityschema.StringAttribute{
+				Description:       "Identifier of the trust policy.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+// trustPolicyResourceModelV0 is the state of the trust policy before the
+// schema was versioned.
+type trustPolicyResourceModelV0 struct {
+	Id        string `json:"id"`
+	ProjectId string `json:"project_id"`
+	Github    *struct {
+		Owner      string `json:"owner"`
+		Repository string `json:"repository"`
+	} `json:"github"`
+	Buildkite *struct {
+		Organization string `json:"organization"`
+		Pipeline     string `json:"pipeline"`
+	} `json:"buildkite"`
+	Circleci *struct {
+		Organization string `json:"organization"`
+		Project      string `json:"project"`
+	} `json:"circleci"`
+}
+
+func (r *TrustPolicyResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			StateUpgrader: upgradeTrustPolicyStateV0,
+		},
+	}
+}
+
+func upgradeTrustPolicyStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior trustPolicyResourceModelV0
+
+	if err := json.Unmarshal(req.RawState.JSON, &prior); err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to read version 0 of the trust policy state, got error: %s", err))
+		return
+	}
+
+	data := TrustPolicyResourceModel{
+		Id:        types.StringValue(prior.Id),
+		ProjectId: types.StringValue(prior.ProjectId),
+		Github:    types.ObjectNull(githubAttrTypes),
+		Buildkite: types.ObjectNull(buildkiteAttrTypes),
+		Circleci:  types.ObjectNull(circleciAttrTypes),
+	}
+
+	if prior.Github != nil {
+		data.Github = types.ObjectValueMust(
+			githubAttrTypes,
+			map[string]attr.Value{
+				"owner":      types.StringValue(prior.Github.Owner),
+				"repository": types.StringValue(prior.Github.Repository),
+			},
+		)
+	}
+
+	if prior.Buildkite != nil {
+		data.Buildkite = types.ObjectValueMust(
+			buildkiteAttrTypes,
+			map[string]attr.Value{
+				"organization": types.StringValue(prior.Buildkite.Organization),
+				"pipeline":     types.StringValue(prior.Buildkite.Pipeline),
+			},
+		)
+	}
+
+	if prior.Circleci != nil {
+		data.Circleci = types.ObjectValueMust(
+			circleciAttrTypes,
+			map[string]attr.Value{
+				"organization": types.StringValue(prior.Circleci.Organization),
+				"project":      types.StringValue(prior.Circleci.Project),
+			},
+		)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
 func (r *TrustPolicyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*corev1connect.ProjectServiceClient)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
+	data := getProviderData(req.ProviderData, &resp.Diagnostics)
 
+	if data == nil {
 		return
 	}
 
-	r.client = *client
+	r.client = data.ProjectClient
 }
 
 func (r *TrustPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startResourceSpan(ctx, "depot_trust_policy", "Create")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data *TrustPolicyResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -265,44 +364,23 @@ func (r *TrustPolicyResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	tflog.Trace(ctx, "created a trust policy")
+	setSpanResourceId(span, response.Msg.TrustPolicy.TrustPolicyId)
 
-	data.Id = types.StringValue(response.Msg.TrustPolicy.TrustPolicyId)
-	data.ProjectId = types.StringValue(data.ProjectId.ValueString())
+	tflog.Trace(ctx, "created a trust policy")
 
-	if response.Msg.TrustPolicy.GetGithub() != nil {
-		data.Github = types.ObjectValueMust(
-			githubAttrTypes,
-			map[string]attr.Value{
-				"owner":      types.StringValue(response.Msg.TrustPolicy.GetGithub().RepositoryOwner),
-				"repository": types.StringValue(response.Msg.TrustPolicy.GetGithub().Repository),
-			},
-		)
-	} else if response.Msg.TrustPolicy.GetBuildkite() != nil {
-		data.Buildkite = types.ObjectValueMust(
-			buildkiteAttrTypes,
-			map[string]attr.Value{
-				"organization": types.StringValue(response.Msg.TrustPolicy.GetBuildkite().OrganizationSlug),
-				"pipeline":     types.StringValue(response.Msg.TrustPolicy.GetBuildkite().PipelineSlug),
-			},
-		)
-	} else if response.Msg.TrustPolicy.GetCircleci() != nil {
-		data.Circleci = types.ObjectValueMust(
-			circleciAttrTypes,
-			map[string]attr.Value{
-				"organization": types.StringValue(response.Msg.TrustPolicy.GetCircleci().OrganizationUuid),
-				"project":      types.StringValue(response.Msg.TrustPolicy.GetCircleci().ProjectUuid),
-			},
-		)
-	} else {
-		resp.Diagnostics.AddError("Invalid Response", "Trust policy must have exactly one provider.")
+	if err := data.setTrustPolicy(data.ProjectId.ValueString(), response.Msg.TrustPolicy); err != nil {
+		resp.Diagnostics.AddError("Invalid Response", fmt.Sprintf("Unable to read trust policy, got error: %s", err))
 		return
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, TrustPolicyResourceIdentityModel{ProjectId: data.ProjectId, Id: data.Id})...)
 }
 
 func (r *TrustPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startResourceSpan(ctx, "depot_trust_policy", "Read")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data *TrustPolicyResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -311,6 +389,8 @@ func (r *TrustPolicyResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	setSpanResourceId(span, data.Id.ValueString())
+
 	response, err := r.client.ListTrustPolicies(ctx, &connect.Request[corev1.ListTrustPoliciesRequest]{
 		Msg: &corev1.ListTrustPoliciesRequest{
 			ProjectId: data.ProjectId.ValueString(),
@@ -329,42 +409,19 @@ func (r *TrustPolicyResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	data.Id = types.StringValue(trustPolicy.TrustPolicyId)
-	data.ProjectId = types.StringValue(data.ProjectId.ValueString())
-
-	if trustPolicy.GetGithub() != nil {
-		data.Github = types.ObjectValueMust(
-			githubAttrTypes,
-			map[string]attr.Value{
-				"owner":      types.StringValue(trustPolicy.GetGithub().RepositoryOwner),
-				"repository": types.StringValue(trustPolicy.GetGithub().Repository),
-			},
-		)
-	} else if trustPolicy.GetBuildkite() != nil {
-		data.Buildkite = types.ObjectValueMust(
-			buildkiteAttrTypes,
-			map[string]attr.Value{
-				"organization": types.StringValue(trustPolicy.GetBuildkite().OrganizationSlug),
-				"pipeline":     types.StringValue(trustPolicy.GetBuildkite().PipelineSlug),
-			},
-		)
-	} else if trustPolicy.GetCircleci() != nil {
-		data.Circleci = types.ObjectValueMust(
-			circleciAttrTypes,
-			map[string]attr.Value{
-				"organization": types.StringValue(trustPolicy.GetCircleci().OrganizationUuid),
-				"project":      types.StringValue(trustPolicy.GetCircleci().ProjectUuid),
-			},
-		)
-	} else {
-		resp.Diagnostics.AddError("Invalid Response", "Trust policy must have exactly one provider.")
+	if err := data.setTrustPolicy(data.ProjectId.ValueString(), trustPolicy); err != nil {
+		resp.Diagnostics.AddError("Invalid Response", fmt.Sprintf("Unable to read trust policy, got error: %s", err))
 		return
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, TrustPolicyResourceIdentityModel{ProjectId: data.ProjectId, Id: data.Id})...)
 }
 
 func (r *TrustPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startResourceSpan(ctx, "depot_trust_policy", "Update")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var state *TrustPolicyResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &state)...)
@@ -373,10 +430,16 @@ func (r *TrustPolicyResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	setSpanResourceId(span, state.Id.ValueString())
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, TrustPolicyResourceIdentityModel{ProjectId: state.ProjectId, Id: state.Id})...)
 }
 
 func (r *TrustPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startResourceSpan(ctx, "depot_trust_policy", "Delete")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data *TrustPolicyResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -385,6 +448,8 @@ func (r *TrustPolicyResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	setSpanResourceId(span, data.Id.ValueString())
+
 	_, err := r.client.RemoveTrustPolicy(ctx, &connect.Request[corev1.RemoveTrustPolicyRequest]{
 		Msg: &corev1.RemoveTrustPolicyRequest{
 			ProjectId:     data.ProjectId.ValueString(),
@@ -401,19 +466,52 @@ func (r *TrustPolicyResource) Delete(ctx context.Context, req resource.DeleteReq
 }
 
 func (r *TrustPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	parts := strings.Split(req.ID, ":")
+	// Import blocks with an identity don't have an import identifier.
+	if req.ID == "" {
+		var identity TrustPolicyResourceIdentityModel
 
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		resp.Diagnostics.AddError(
-			"Unexpected Import Identifier",
-			fmt.Sprintf("Expected import identifier with format: project_id:trust_policy_id. Got: %q", req.ID),
-		)
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.Id)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), identity.ProjectId)...)
 
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), parts[0])...)
+	projectId, id, err := parseTrustPolicyImportId(req.ID)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected Import Identifier", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), projectId)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, TrustPolicyResourceIdentityModel{
+		ProjectId: types.StringValue(projectId),
+		Id:        types.StringValue(id),
+	})...)
+}
+
+// parseTrustPolicyImportId splits an import identifier with the format
+// `project_id:trust_policy_id` into its parts.
+func parseTrustPolicyImportId(importId string) (string, string, error) {
+	parts := strings.Split(importId, ":")
+
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("Expected import identifier with format: project_id:trust_policy_id. Got: %q", importId)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// formatTrustPolicyImportId returns the import identifier of a trust policy.
+func formatTrustPolicyImportId(projectId string, id string) string {
+	return projectId + ":" + id
 }
 
 func findTrustPolicy(ctx context.Context, policies []*corev1.TrustPolicy, id string) (*corev1.TrustPolicy, error) {
@@ -425,3 +523,43 @@ func findTrustPolicy(ctx context.Context, policies []*corev1.TrustPolicy, id str
 
 	return nil, fmt.Errorf("trust policy doesn't exist")
 }
+
+// setTrustPolicy sets the attributes of the model from the trust policy
+// returned by Depot.
+func (data *TrustPolicyResourceModel) setTrustPolicy(projectId string, trustPolicy *corev1.TrustPolicy) error {
+	data.Id = types.StringValue(trustPolicy.TrustPolicyId)
+	data.ProjectId = types.StringValue(projectId)
+	data.Github = types.ObjectNull(githubAttrTypes)
+	data.Buildkite = types.ObjectNull(buildkiteAttrTypes)
+	data.Circleci = types.ObjectNull(circleciAttrTypes)
+
+	if trustPolicy.GetGithub() != nil {
+		data.Github = types.ObjectValueMust(
+			githubAttrTypes,
+			map[string]attr.Value{
+				"owner":      types.StringValue(trustPolicy.GetGithub().RepositoryOwner),
+				"repository": types.StringValue(trustPolicy.GetGithub().Repository),
+			},
+		)
+	} else if trustPolicy.GetBuildkite() != nil {
+		data.Buildkite = types.ObjectValueMust(
+			buildkiteAttrTypes,
+			map[string]attr.Value{
+				"organization": types.StringValue(trustPolicy.GetBuildkite().OrganizationSlug),
+				"pipeline":     types.StringValue(trustPolicy.GetBuildkite().PipelineSlug),
+			},
+		)
+	} else if trustPolicy.GetCircleci() != nil {
+		data.Circleci = types.ObjectValueMust(
+			circleciAttrTypes,
+			map[string]attr.Value{
+				"organization": types.StringValue(trustPolicy.GetCircleci().OrganizationUuid),
+				"project":      types.StringValue(trustPolicy.GetCircleci().ProjectUuid),
+			},
+		)
+	} else {
+		return fmt.Errorf("trust policy must have exactly one provider")
+	}
+
+	return nil
+}
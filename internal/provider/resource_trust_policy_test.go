@@ -1,11 +1,20 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestAccTrustPolicyResourceDefault(t *testing.T) {
@@ -56,6 +65,81 @@ func TestAccTrustPolicyResourceDefault(t *testing.T) {
 	})
 }
 
+func TestAccTrustPolicyResourceIdentity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccTrustPolicyResourceConfigDefault(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("depot_trust_policy.test", map[string]knownvalue.Check{
+						"project_id": knownvalue.StringExact("7f76t7vghb"),
+						"id":         knownvalue.NotNull(),
+					}),
+				},
+			},
+			// ImportState with identity testing
+			{
+				ResourceName:    "depot_trust_policy.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestTrustPolicyResourceUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &TrustPolicyResource{}
+
+	var schemaResp fwresource.SchemaResponse
+
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	resp := fwresource.UpgradeStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	r.UpgradeState(ctx)[0].StateUpgrader(ctx, fwresource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{
+			JSON: []byte(`{"id":"tp","project_id":"abc","github":{"owner":"terraform-community-providers","repository":"terraform-provider-depot"},"buildkite":null,"circleci":null}`),
+		},
+	}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data TrustPolicyResourceModel
+
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if data.Id.ValueString() != "tp" || data.ProjectId.ValueString() != "abc" {
+		t.Errorf("unexpected trust policy: %v", data)
+	}
+
+	if owner := data.Github.Attributes()["owner"].(types.String).ValueString(); owner != "terraform-community-providers" {
+		t.Errorf("got github owner %q", owner)
+	}
+
+	if !data.Buildkite.IsNull() || !data.Circleci.IsNull() {
+		t.Errorf("expected buildkite and circleci to be null")
+	}
+}
+
 func testAccTrustPolicyResourceConfigDefault() string {
 	return `
 resource "depot_trust_policy" "test" {
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/otelconnect"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/terraform-community-providers/terraform-provider-depot"
+
+// ConfigureTracing installs a global OpenTelemetry tracer provider which
+// exports spans over OTLP when enabled through the standard `OTEL_*`
+// environment variables. Otherwise the global no-op tracer provider is kept.
+// The returned function flushes and stops the tracer provider.
+func ConfigureTracing(ctx context.Context, version string) (func(context.Context) error, error) {
+	if !tracingEnabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := sdkresource.New(
+		ctx,
+		sdkresource.WithAttributes(
+			semconv.ServiceName("terraform-provider-depot"),
+			semconv.ServiceVersion(version),
+		),
+		sdkresource.WithFromEnv(),
+		sdkresource.WithTelemetrySDK(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp.Shutdown, nil
+}
+
+func tracingEnabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+
+	switch os.Getenv("OTEL_TRACES_EXPORTER") {
+	case "otlp":
+		return true
+	case "":
+		return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+	default:
+		return false
+	}
+}
+
+// newTracingInterceptor creates a span for every RPC made to the Depot API
+// using the global tracer provider.
+func newTracingInterceptor() (connect.Interceptor, error) {
+	return otelconnect.NewInterceptor(otelconnect.WithoutMetrics())
+}
+
+// startResourceSpan starts a span for a CRUD operation of a resource.
+func startResourceSpan(ctx context.Context, resourceType string, operation string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(
+		ctx,
+		resourceType+"."+operation,
+		trace.WithAttributes(
+			attribute.String("depot.resource.type", resourceType),
+			attribute.String("depot.resource.operation", operation),
+		),
+	)
+}
+
+func setSpanResourceId(span trace.Span, id string) {
+	if id != "" {
+		span.SetAttributes(attribute.String("depot.resource.id", id))
+	}
+}
+
+// endResourceSpan records the error diagnostics on the span and ends it.
+func endResourceSpan(span trace.Span, diags diag.Diagnostics) {
+	if diags.HasError() {
+		errs := diags.Errors()
+		span.SetStatus(codes.Error, errs[0].Summary()+": "+errs[0].Detail())
+	}
+
+	span.End()
+}
@@ -36,7 +36,17 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	shutdownTracing, err := provider.ConfigureTracing(context.Background(), version)
+
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	err = providerserver.Serve(context.Background(), provider.New(version), opts)
+
+	if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+		log.Printf("unable to flush traces: %s", shutdownErr)
+	}
 
 	if err != nil {
 		log.Fatal(err.Error())